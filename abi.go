@@ -2,10 +2,12 @@ package abi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"hash"
 	"io"
+	"reflect"
 	"regexp"
 	"strings"
 	"sync"
@@ -21,6 +23,10 @@ type ABI struct {
 	MethodsBySignature map[string]*Method
 	Events             map[string]*Event
 	Errors             map[string]*Error
+
+	// Resolver looks up the signatures of the selectors that are not
+	// in the abi when decoding calldata (optional)
+	Resolver SignatureResolver
 }
 
 func (a *ABI) GetMethod(name string) *Method {
@@ -33,6 +39,55 @@ func (a *ABI) GetMethodBySignature(methodSignature string) *Method {
 	return m
 }
 
+// GetMethodByID returns the method with the given selector or nil if not found
+func (a *ABI) GetMethodByID(id [4]byte) *Method {
+	for _, m := range a.Methods {
+		if bytes.Equal(m.ID(), id[:]) {
+			return m
+		}
+	}
+	return nil
+}
+
+// DecodeCalldata looks up the method with the selector of the calldata
+// and returns its name and the decoded named arguments. Unknown selectors
+// are looked up with the Resolver if set, in which case the arguments
+// are named with their positions
+func (a *ABI) DecodeCalldata(input []byte) (string, map[string]interface{}, error) {
+	return a.DecodeCalldataContext(context.Background(), input)
+}
+
+// DecodeCalldataContext is like DecodeCalldata with a context for the
+// lookups of the Resolver
+func (a *ABI) DecodeCalldataContext(ctx context.Context, input []byte) (string, map[string]interface{}, error) {
+	selector, _, err := SplitCalldata(input)
+	if err != nil {
+		return "", nil, err
+	}
+	m := a.GetMethodByID(selector)
+	if m == nil && a.Resolver != nil {
+		return resolveCalldata(ctx, a.Resolver, selector, input)
+	}
+	if m == nil {
+		return "", nil, fmt.Errorf("no method found for selector 0x%x", selector)
+	}
+	args, err := m.DecodeInputs(input)
+	if err != nil {
+		return "", nil, err
+	}
+	return m.Name, args, nil
+}
+
+// GetEvent returns the event with the given name or nil if not found
+func (a *ABI) GetEvent(name string) *Event {
+	return a.Events[name]
+}
+
+// GetError returns the error with the given name or nil if not found
+func (a *ABI) GetError(name string) *Error {
+	return a.Errors[name]
+}
+
 func (a *ABI) addError(e *Error) {
 	if len(a.Errors) == 0 {
 		a.Errors = map[string]*Error{}
@@ -100,8 +155,22 @@ func NewABIFromReader(r io.Reader) (*ABI, error) {
 	return abi, nil
 }
 
-// UnmarshalJSON implements json.Unmarshaler interface
+// UnmarshalJSON implements json.Unmarshaler interface. It accepts either
+// the abi array or a compiler artifact object with an 'abi' field
 func (a *ABI) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) != 0 && trimmed[0] == '{' {
+		var artifact struct {
+			Abi json.RawMessage
+		}
+		if err := json.Unmarshal(trimmed, &artifact); err != nil {
+			return err
+		}
+		if artifact.Abi == nil {
+			return fmt.Errorf("artifact does not have an abi field")
+		}
+		return a.UnmarshalJSON(artifact.Abi)
+	}
+
 	var fields []struct {
 		Type            string
 		Name            string
@@ -124,7 +193,7 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 			}
 			input, err := NewTupleTypeFromArgs(field.Inputs)
 			if err != nil {
-				panic(err)
+				return fmt.Errorf("failed to parse %s '%s': %v", field.Type, field.Name, err)
 			}
 			a.Constructor = &Method{
 				Inputs: input,
@@ -138,11 +207,11 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 
 			inputs, err := NewTupleTypeFromArgs(field.Inputs)
 			if err != nil {
-				panic(err)
+				return fmt.Errorf("failed to parse %s '%s': %v", field.Type, field.Name, err)
 			}
 			outputs, err := NewTupleTypeFromArgs(field.Outputs)
 			if err != nil {
-				panic(err)
+				return fmt.Errorf("failed to parse %s '%s': %v", field.Type, field.Name, err)
 			}
 			method := &Method{
 				Name:    field.Name,
@@ -155,7 +224,7 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 		case "event":
 			input, err := NewTupleTypeFromArgs(field.Inputs)
 			if err != nil {
-				panic(err)
+				return fmt.Errorf("failed to parse %s '%s': %v", field.Type, field.Name, err)
 			}
 			event := &Event{
 				Name:      field.Name,
@@ -167,7 +236,7 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 		case "error":
 			input, err := NewTupleTypeFromArgs(field.Inputs)
 			if err != nil {
-				panic(err)
+				return fmt.Errorf("failed to parse %s '%s': %v", field.Type, field.Name, err)
 			}
 			errObj := &Error{
 				Name:   field.Name,
@@ -194,7 +263,8 @@ type Method struct {
 	Outputs *Type
 }
 
-// Sig returns the signature of the method
+// Sig returns the canonical signature of the method with the tuples
+// expanded (i.e. 'transfer(address,uint256)')
 func (m *Method) Sig() string {
 	return buildSignature(m.Name, m.Inputs)
 }
@@ -218,17 +288,44 @@ func (m *Method) Encode(args interface{}) ([]byte, error) {
 	return data, nil
 }
 
+// EncodeCall encodes the calldata of a call to this function with the
+// arguments in order, like the abi.encodeCall builtin of solidity. The
+// number of arguments and their go types are checked before encoding
+func (m *Method) EncodeCall(args ...interface{}) ([]byte, error) {
+	elems := m.Inputs.TupleElems()
+	if len(args) != len(elems) {
+		return nil, fmt.Errorf("method %s expects %d arguments but found %d", m.Sig(), len(elems), len(args))
+	}
+	for indx, arg := range args {
+		if arg == nil {
+			continue
+		}
+		if err := checkGoType(reflect.TypeOf(arg), elems[indx].Elem); err != nil {
+			return nil, fmt.Errorf("argument '%s' of method %s: %v", tupleElemName(elems[indx], indx), m.Sig(), err)
+		}
+	}
+	return m.Encode(args)
+}
+
 // Decode decodes the output with this function
 func (m *Method) Decode(data []byte) (map[string]interface{}, error) {
-	if len(data) == 0 {
-		return nil, fmt.Errorf("empty response")
+	return DecodeReturns(m.Outputs, data)
+}
+
+// DecodeInputs decodes the calldata of a call to this function, including
+// the selector, into the named input values
+func (m *Method) DecodeInputs(data []byte) (map[string]interface{}, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("calldata too short, expected at least 4 bytes but found %d", len(data))
+	}
+	if !bytes.Equal(data[:4], m.ID()) {
+		return nil, fmt.Errorf("selector 0x%x does not match method %s", data[:4], m.Sig())
 	}
-	respInterface, err := Decode(m.Outputs, data)
+	val, err := Decode(m.Inputs, data[4:])
 	if err != nil {
 		return nil, err
 	}
-	resp := respInterface.(map[string]interface{})
-	return resp, nil
+	return val.(map[string]interface{}), nil
 }
 
 // MustNewMethod creates a new solidity method object or fails
@@ -337,6 +434,16 @@ type Error struct {
 	Inputs *Type
 }
 
+// Sig returns the signature of the error
+func (e *Error) Sig() string {
+	return buildSignature(e.Name, e.Inputs)
+}
+
+// ID returns the selector of the error in the revert data
+func (e *Error) ID() []byte {
+	return keccak256([]byte(e.Sig()))[:4]
+}
+
 // NewError creates a new solidity error object
 func NewError(name string) (*Error, error) {
 	name, typ, err := parseEventOrErrorSignature("error ", name)
@@ -388,18 +495,33 @@ func (e *Event) Match(log *ethgo.Log) bool {
 
 // ParseLog parses a log with this event
 func (e *Event) ParseLog(log *ethgo.Log) (map[string]interface{}, error) {
+	if e.Anonymous {
+		// anonymous events do not have the signature topic
+		return DecodeLogArgs(e.Inputs, log.Topics, log.Data)
+	}
 	if !e.Match(log) {
 		return nil, fmt.Errorf("log does not match this event")
 	}
 	return e.Inputs.ParseLog(log)
 }
 
-func buildSignature(name string, typ *Type) string {
-	types := make([]string, len(typ.tuple))
-	for i, input := range typ.tuple {
-		types[i] = strings.Replace(input.Elem.String(), "tuple", "", -1)
+// DecodeLog decodes the raw topics and data of a log emitted by this event.
+// The topics include the signature topic unless the event is anonymous
+func (e *Event) DecodeLog(topics []ethgo.Hash, data []byte) (map[string]interface{}, error) {
+	return e.ParseLog(&ethgo.Log{Topics: topics, Data: data})
+}
+
+// ParseLogInto parses a log with this event into the out struct
+func (e *Event) ParseLogInto(log *ethgo.Log, out interface{}) error {
+	val, err := e.ParseLog(log)
+	if err != nil {
+		return err
 	}
-	return fmt.Sprintf("%v(%v)", name, strings.Join(types, ","))
+	return decodeInto(val, out)
+}
+
+func buildSignature(name string, typ *Type) string {
+	return name + typ.Signature()
 }
 
 // ArgumentStr encodes a type object
@@ -426,6 +548,17 @@ func releaseKeccak(k hash.Hash) {
 	keccakPool.Put(k)
 }
 
+// keccak256 returns the keccak256 hash of the concatenated input
+func keccak256(data ...[]byte) []byte {
+	k := acquireKeccak()
+	for _, b := range data {
+		k.Write(b)
+	}
+	dst := k.Sum(nil)
+	releaseKeccak(k)
+	return dst
+}
+
 func NewABIFromList(humanReadableAbi []string) (*ABI, error) {
 	res := &ABI{}
 	for _, c := range humanReadableAbi {
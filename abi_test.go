@@ -1,11 +1,13 @@
 package abi
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
 )
 
 func TestAbi(t *testing.T) {
@@ -362,3 +364,111 @@ func TestAbi_ParseMethodSignature(t *testing.T) {
 		}
 	}
 }
+
+func TestMethod_Signature(t *testing.T) {
+	m, err := NewMethod("function fill(tuple(address maker, tuple(uint256 id)[] items) order, bytes signature)")
+	require.NoError(t, err)
+	assert.Equal(t, "fill((address,(uint256)[]),bytes)", m.Sig())
+}
+
+func TestAbi_Artifact(t *testing.T) {
+	artifact := `{
+		"contractName": "Token",
+		"abi": [
+			{"type": "function", "name": "balanceOf", "stateMutability": "view", "inputs": [{"name": "owner", "type": "address"}], "outputs": [{"name": "balance", "type": "uint256"}]},
+			{"type": "event", "name": "Transfer", "inputs": [{"name": "from", "type": "address", "indexed": true}, {"name": "value", "type": "uint256"}]},
+			{"type": "error", "name": "Unauthorized", "inputs": [{"name": "caller", "type": "address"}]}
+		],
+		"bytecode": "0x"
+	}`
+
+	abi, err := NewABI(artifact)
+	require.NoError(t, err)
+
+	method := abi.GetMethod("balanceOf")
+	require.NotNil(t, method)
+	require.True(t, method.Const)
+	require.Equal(t, "tuple(uint256 balance)", method.Outputs.Format(true))
+
+	require.Equal(t, "Transfer(address,uint256)", abi.GetEvent("Transfer").Sig())
+	require.Equal(t, "tuple(address caller)", abi.GetError("Unauthorized").Inputs.Format(true))
+	require.Nil(t, abi.GetEvent("Approval"))
+
+	// the artifact must have an abi
+	_, err = NewABI(`{"bytecode": "0x"}`)
+	require.Error(t, err)
+
+	// invalid types are returned as errors
+	_, err = NewABI(`[{"type": "function", "name": "a", "inputs": [{"type": "uint7"}]}]`)
+	require.Error(t, err)
+}
+
+func TestMethod_DecodeInputs(t *testing.T) {
+	method := MustNewMethod("function transfer(address to, uint256 amount) returns (bool)")
+	require.Equal(t, []byte{0xa9, 0x05, 0x9c, 0xbb}, method.ID())
+
+	args := map[string]interface{}{
+		"to":     ethgo.Address{0x1},
+		"amount": big.NewInt(1000),
+	}
+	calldata, err := method.Encode(args)
+	require.NoError(t, err)
+	require.Len(t, calldata, 4+64)
+
+	res, err := method.DecodeInputs(calldata)
+	require.NoError(t, err)
+	require.Equal(t, args, res)
+
+	_, err = method.DecodeInputs(calldata[:3])
+	require.Error(t, err)
+
+	_, err = method.DecodeInputs(append([]byte{0x1, 0x2, 0x3, 0x4}, calldata[4:]...))
+	require.EqualError(t, err, "selector 0x01020304 does not match method transfer(address,uint256)")
+}
+
+func TestMethod_EncodeCall(t *testing.T) {
+	method := MustNewMethod("function transfer(address to, uint256 amount) returns (bool)")
+
+	expected, err := method.Encode(map[string]interface{}{
+		"to":     ethgo.Address{0x1},
+		"amount": big.NewInt(1000),
+	})
+	require.NoError(t, err)
+
+	res, err := method.EncodeCall(ethgo.Address{0x1}, big.NewInt(1000))
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	_, err = method.EncodeCall(ethgo.Address{0x1})
+	require.EqualError(t, err, "method transfer(address,uint256) expects 2 arguments but found 1")
+
+	_, err = method.EncodeCall(ethgo.Address{0x1}, true)
+	require.EqualError(t, err, "argument 'amount' of method transfer(address,uint256): go type bool cannot be encoded as uint256")
+}
+
+func TestAbi_DecodeCalldata(t *testing.T) {
+	abi, err := NewABIFromList([]string{
+		"function transfer(address to, uint256 amount) returns (bool)",
+		"function transfer(address from, address to, uint256 amount) returns (bool)",
+		"function approve(address spender, uint256 amount) returns (bool)",
+	})
+	require.NoError(t, err)
+
+	calldata, err := EncodeWithSignature("transfer(address,address,uint256)", ethgo.Address{0x1}, ethgo.Address{0x2}, big.NewInt(10))
+	require.NoError(t, err)
+
+	name, args, err := abi.DecodeCalldata(calldata)
+	require.NoError(t, err)
+	require.Equal(t, "transfer", name)
+	require.Equal(t, map[string]interface{}{
+		"from":   ethgo.Address{0x1},
+		"to":     ethgo.Address{0x2},
+		"amount": big.NewInt(10),
+	}, args)
+
+	_, _, err = abi.DecodeCalldata([]byte{0x1, 0x2, 0x3, 0x4})
+	require.EqualError(t, err, "no method found for selector 0x01020304")
+
+	_, _, err = abi.DecodeCalldata([]byte{0x1})
+	require.Error(t, err)
+}
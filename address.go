@@ -0,0 +1,47 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+)
+
+// DecodePackedAsciiAddress decodes an address stored as 40 ascii hex
+// characters (optionally with the 0x prefix) at the start of the input
+// and returns the remaining input. Mixed case addresses are validated
+// against their EIP-55 checksum
+func DecodePackedAsciiAddress(input []byte) (ethgo.Address, []byte, error) {
+	var addr ethgo.Address
+
+	data := input
+	if bytes.HasPrefix(data, []byte("0x")) || bytes.HasPrefix(data, []byte("0X")) {
+		data = data[2:]
+	}
+	if len(data) < 40 {
+		return addr, nil, fmt.Errorf("ascii address requires 40 hex characters but input has %d", len(data))
+	}
+	str := string(data[:40])
+	if _, err := hex.Decode(addr[:], data[:40]); err != nil {
+		return addr, nil, fmt.Errorf("could not decode hex: %v", err)
+	}
+	if err := validateChecksum(str, addr); err != nil {
+		return addr, nil, err
+	}
+	return addr, data[40:], nil
+}
+
+// validateChecksum checks the EIP-55 checksum of the hex representation
+// of the address. All lower or upper case strings have no checksum
+func validateChecksum(str string, addr ethgo.Address) error {
+	str = strings.TrimPrefix(str, "0x")
+	if str == strings.ToLower(str) || str == strings.ToUpper(str) {
+		return nil
+	}
+	if expected := addr.String(); expected[2:] != str {
+		return fmt.Errorf("invalid checksum for address 0x%s, expected %s", str, expected)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestDecodePackedAsciiAddress(t *testing.T) {
+	expected := ethgo.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+	cases := []string{
+		"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		"5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED",
+	}
+	for _, c := range cases {
+		addr, tail, err := DecodePackedAsciiAddress(append([]byte(c), 0x1, 0x2))
+		require.NoError(t, err)
+		require.Equal(t, expected, addr)
+		require.Equal(t, []byte{0x1, 0x2}, tail)
+	}
+
+	invalid := []string{
+		// bad checksum
+		"0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		// not hex
+		"0xzaaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		// too short
+		"0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea",
+	}
+	for _, c := range invalid {
+		_, _, err := DecodePackedAsciiAddress([]byte(c))
+		require.Error(t, err)
+	}
+}
@@ -0,0 +1,42 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// checksumLength is the length of the checksum appended to the packed payload
+const checksumLength = 4
+
+// EncodePackedWithChecksum packs the values with their types and appends
+// the first 4 bytes of the keccak256 hash of the payload as a checksum
+func EncodePackedWithChecksum(types []*Type, values []interface{}) ([]byte, error) {
+	payload, err := encodePackedList(types, values, &EncodeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return append(payload, keccak256(payload)[:checksumLength]...), nil
+}
+
+// VerifyPackedChecksum validates the checksum at the end of the input
+// and returns the payload without it
+func VerifyPackedChecksum(input []byte) ([]byte, error) {
+	if len(input) < checksumLength {
+		return nil, fmt.Errorf("input too short to contain a checksum")
+	}
+	payload, checksum := input[:len(input)-checksumLength], input[len(input)-checksumLength:]
+	if !bytes.Equal(keccak256(payload)[:checksumLength], checksum) {
+		return nil, fmt.Errorf("checksum mismatch")
+	}
+	return payload, nil
+}
+
+// DecodePackedWithChecksum validates and strips the checksum of the input
+// and decodes the payload with the types, which must take all of it
+func DecodePackedWithChecksum(types []*Type, input []byte) ([]interface{}, error) {
+	payload, err := VerifyPackedChecksum(input)
+	if err != nil {
+		return nil, err
+	}
+	return DecodePackedValues(types, payload)
+}
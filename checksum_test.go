@@ -0,0 +1,47 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestPackedChecksum(t *testing.T) {
+	types := []*Type{
+		MustNewType("address"),
+		MustNewType("uint64"),
+		MustNewType("string"),
+	}
+	values := []interface{}{
+		ethgo.Address{0x1},
+		uint64(10),
+		"hello",
+	}
+
+	res, err := EncodePackedWithChecksum(types, values)
+	require.NoError(t, err)
+	require.Len(t, res, 20+8+5+4)
+
+	found, err := DecodePackedWithChecksum(types, res)
+	require.NoError(t, err)
+	require.Equal(t, values, found)
+
+	// tamper the payload
+	res[0] = 0xff
+	_, err = DecodePackedWithChecksum(types, res)
+	require.Error(t, err)
+
+	_, err = VerifyPackedChecksum([]byte{0x1})
+	require.Error(t, err)
+
+	// the bytes left after the values are rejected even with a valid checksum
+	static := types[:2]
+	res, err = EncodePackedWithChecksum(static, values[:2])
+	require.NoError(t, err)
+
+	payload := append(res[:len(res)-checksumLength:len(res)-checksumLength], 0x1, 0x2)
+	input := append(payload, keccak256(payload)[:checksumLength]...)
+	_, err = DecodePackedWithChecksum(static, input)
+	require.EqualError(t, err, "2 trailing bytes after decoding 2 values")
+}
@@ -0,0 +1,38 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// ToUint64 converts a decoded integer (a native integer or a big.Int)
+// into an uint64, returning an error if it does not fit
+func ToUint64(v interface{}) (uint64, error) {
+	var res uint64
+	if err := convertInt(v, &res); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+// ToInt64 converts a decoded integer (a native integer or a big.Int)
+// into an int64, returning an error if it does not fit
+func ToInt64(v interface{}) (int64, error) {
+	var res int64
+	if err := convertInt(v, &res); err != nil {
+		return 0, err
+	}
+	return res, nil
+}
+
+func convertInt(v interface{}, out interface{}) error {
+	if num, ok := v.(big.Int); ok {
+		v = &num
+	}
+	val := reflect.ValueOf(v)
+	if isNil(val) {
+		return fmt.Errorf("cannot convert nil value")
+	}
+	return setValue(reflect.ValueOf(out).Elem(), val)
+}
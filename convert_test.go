@@ -0,0 +1,71 @@
+package abi
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestToUint64(t *testing.T) {
+	maxUint64 := new(big.Int).SetUint64(math.MaxUint64)
+
+	cases := []struct {
+		input    interface{}
+		expected uint64
+	}{
+		{uint8(1), 1},
+		{int16(2), 2},
+		{uint64(math.MaxUint64), math.MaxUint64},
+		{big.NewInt(10), 10},
+		{*big.NewInt(10), 10},
+		{maxUint64, math.MaxUint64},
+	}
+	for _, c := range cases {
+		res, err := ToUint64(c.input)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, res)
+	}
+
+	invalid := []interface{}{
+		new(big.Int).Add(maxUint64, big.NewInt(1)),
+		big.NewInt(-1),
+		int8(-1),
+		"1",
+		nil,
+		(*big.Int)(nil),
+	}
+	for _, c := range invalid {
+		_, err := ToUint64(c)
+		require.Error(t, err)
+	}
+}
+
+func TestToInt64(t *testing.T) {
+	cases := []struct {
+		input    interface{}
+		expected int64
+	}{
+		{int8(-1), -1},
+		{uint32(2), 2},
+		{uint64(math.MaxInt64), math.MaxInt64},
+		{big.NewInt(math.MinInt64), math.MinInt64},
+		{big.NewInt(math.MaxInt64), math.MaxInt64},
+	}
+	for _, c := range cases {
+		res, err := ToInt64(c.input)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, res)
+	}
+
+	invalid := []interface{}{
+		uint64(math.MaxInt64 + 1),
+		new(big.Int).Add(big.NewInt(math.MaxInt64), big.NewInt(1)),
+		new(big.Int).Sub(big.NewInt(math.MinInt64), big.NewInt(1)),
+	}
+	for _, c := range invalid {
+		_, err := ToInt64(c)
+		require.Error(t, err)
+	}
+}
@@ -1,12 +1,15 @@
 package abi
 
 import (
+	"database/sql"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"math/big"
 	"reflect"
 	"strconv"
 
+	"github.com/holiman/uint256"
 	"github.com/mitchellh/mapstructure"
 	"github.com/umbracle/ethgo"
 )
@@ -16,21 +19,75 @@ func Decode(t *Type, input []byte) (interface{}, error) {
 	if len(input) == 0 {
 		return nil, fmt.Errorf("empty input")
 	}
-	val, _, err := decode(t, input)
+	val, _, err := decode(t, input, wordSize, &DecodeOptions{})
 	return val, err
 }
 
+// DecodeWithOptions decodes the input with the standard encoding using
+// the word size, the LenientBool and the EnumNames flags of the options
+func DecodeWithOptions(t *Type, input []byte, opts *DecodeOptions) (interface{}, error) {
+	if opts == nil {
+		opts = &DecodeOptions{}
+	}
+	w := wordSize
+	if opts.WordSize != 0 {
+		w = opts.WordSize
+	}
+	if w < 0 {
+		return nil, fmt.Errorf("invalid word size %d", w)
+	}
+	if len(input) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+	val, _, err := decode(t, input, w, opts)
+	return val, err
+}
+
+// DecodeReturns decodes the return data of a contract call with the
+// tuple of the outputs of the method
+func DecodeReturns(outputs *Type, data []byte) (map[string]interface{}, error) {
+	if outputs.Kind() != KindTuple {
+		return nil, fmt.Errorf("expected a tuple type but found %s", outputs.String())
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("empty response")
+	}
+	val, err := Decode(outputs, data)
+	if err != nil {
+		return nil, err
+	}
+	return val.(map[string]interface{}), nil
+}
+
+// DecodeAs decodes the input with a given type into a new value of
+// type T (i.e. a struct for tuple types) like DecodeStruct
+func DecodeAs[T any](t *Type, input []byte) (T, error) {
+	var res T
+	if err := DecodeStruct(t, input, &res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
 // DecodeStruct decodes the input with a type to a struct
 func DecodeStruct(t *Type, input []byte, out interface{}) error {
 	val, err := Decode(t, input)
 	if err != nil {
 		return err
 	}
+	return decodeInto(val, out)
+}
 
+// decodeInto copies the decoded value into the out param
+func decodeInto(val interface{}, out interface{}) error {
+	if decodeIntoFast(val, out) {
+		return nil
+	}
 	dc := &mapstructure.DecoderConfig{
 		Result:           out,
 		WeaklyTypedInput: true,
 		TagName:          "abi",
+		DecodeHook:       decodeHook,
 	}
 	ms, err := mapstructure.NewDecoder(dc)
 	if err != nil {
@@ -42,49 +99,238 @@ func DecodeStruct(t *Type, input []byte, out interface{}) error {
 	return nil
 }
 
-func decode(t *Type, input []byte) (interface{}, []byte, error) {
+// decodeIntoFast assigns the common decoded values to out params of
+// the same type without reflection. It returns false if the value or
+// the out param are not supported
+func decodeIntoFast(val interface{}, out interface{}) bool {
+	switch dst := out.(type) {
+	case *uint64:
+		if v, ok := val.(uint64); ok {
+			*dst = v
+			return true
+		}
+
+	case **big.Int:
+		if v, ok := val.(*big.Int); ok {
+			*dst = v
+			return true
+		}
+
+	case *big.Int:
+		if v, ok := val.(*big.Int); ok {
+			dst.Set(v)
+			return true
+		}
+
+	case *uint256.Int:
+		if v, ok := val.(*uint256.Int); ok {
+			dst.Set(v)
+			return true
+		}
+
+	case *ethgo.Address:
+		if v, ok := val.(ethgo.Address); ok {
+			*dst = v
+			return true
+		}
+
+	case *[32]byte:
+		if v, ok := val.([32]byte); ok {
+			*dst = v
+			return true
+		}
+
+	case *ethgo.Hash:
+		if v, ok := val.([32]byte); ok {
+			*dst = v
+			return true
+		}
+
+	case *string:
+		if v, ok := val.(string); ok {
+			*dst = v
+			return true
+		}
+
+	case *[]byte:
+		if v, ok := val.([]byte); ok {
+			*dst = v
+			return true
+		}
+
+	default:
+		return decodeGethFast(val, out)
+	}
+	return false
+}
+
+var scannerT = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+
+// decodeHook converts the decoded values into the types of the out param
+func decodeHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	data, err := typeHookDecode(to, data)
+	if err != nil {
+		return nil, err
+	}
+	data, err = uint256Hook(to, data)
+	if err != nil {
+		return nil, err
+	}
+	data = gethHook(to, data)
+	// the previous hooks may have converted the data already
+	data, err = scannerHook(reflect.TypeOf(data), to, data)
+	if err != nil {
+		return nil, err
+	}
+	return bigIntHook(to, data)
+}
+
+// bigIntHook converts big integers into native integer fields if
+// the value fits in the field
+func bigIntHook(to reflect.Type, data interface{}) (interface{}, error) {
+	n, ok := data.(*big.Int)
+	if !ok || n == nil {
+		return data, nil
+	}
+	res := reflect.New(to).Elem()
+
+	switch to.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if !n.IsInt64() || res.OverflowInt(n.Int64()) {
+			return nil, fmt.Errorf("value %s overflows %s", n.String(), to.String())
+		}
+		res.SetInt(n.Int64())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if !n.IsUint64() || res.OverflowUint(n.Uint64()) {
+			return nil, fmt.Errorf("value %s overflows %s", n.String(), to.String())
+		}
+		res.SetUint(n.Uint64())
+
+	default:
+		return data, nil
+	}
+	return res.Interface(), nil
+}
+
+// scannerHook decodes values into the types that implement sql.Scanner
+func scannerHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if from == to || from == reflect.PtrTo(to) {
+		// already scanned
+		return data, nil
+	}
+	if to.Kind() == reflect.Ptr && to.Implements(scannerT) {
+		res := reflect.New(to.Elem())
+		if err := res.Interface().(sql.Scanner).Scan(driverValue(data)); err != nil {
+			return nil, err
+		}
+		return res.Interface(), nil
+	}
+	if reflect.PtrTo(to).Implements(scannerT) {
+		res := reflect.New(to)
+		if err := res.Interface().(sql.Scanner).Scan(driverValue(data)); err != nil {
+			return nil, err
+		}
+		return res.Elem().Interface(), nil
+	}
+	return data, nil
+}
+
+// driverValue converts a decoded value into one of the types
+// handled by sql.Scanner implementations
+func driverValue(v interface{}) interface{} {
+	switch obj := v.(type) {
+	case uint8:
+		return int64(obj)
+	case uint16:
+		return int64(obj)
+	case uint32:
+		return int64(obj)
+	case uint64:
+		if obj > math.MaxInt64 {
+			return strconv.FormatUint(obj, 10)
+		}
+		return int64(obj)
+	case int8:
+		return int64(obj)
+	case int16:
+		return int64(obj)
+	case int32:
+		return int64(obj)
+	case *big.Int:
+		if obj.IsInt64() {
+			return obj.Int64()
+		}
+		return obj.String()
+	case ethgo.Address:
+		return obj.String()
+	}
+
+	val := reflect.ValueOf(v)
+	if val.Kind() == reflect.Array && val.Type().Elem().Kind() == reflect.Uint8 {
+		return convertArrayToBytes(val).Bytes()
+	}
+	return v
+}
+
+func decode(t *Type, input []byte, w int, opts *DecodeOptions) (interface{}, []byte, error) {
+	val, tail, err := decodeValue(t, input, w, opts)
+	if err != nil {
+		return nil, nil, t.labelErr(err)
+	}
+	return val, tail, nil
+}
+
+func decodeValue(t *Type, input []byte, w int, opts *DecodeOptions) (interface{}, []byte, error) {
 	var data []byte
 	var length int
 	var err error
 
-	// safe check, input should be at least 32 bytes
-	if len(input) < 32 {
+	if err := checkWordSize(t, w); err != nil {
+		return nil, nil, err
+	}
+
+	// safe check, input should be at least a word
+	if len(input) < w {
 		return nil, nil, fmt.Errorf("incorrect length")
 	}
 
 	if t.isVariableInput() {
-		length, err = readLength(input)
+		length, err = readLength(input, w)
 		if err != nil {
 			return nil, nil, err
 		}
 	} else {
-		data = input[:32]
+		data = input[:w]
 	}
 
 	switch t.kind {
 	case KindTuple:
-		return decodeTuple(t, input)
+		return decodeTuple(t, input, w, opts)
 
 	case KindSlice:
-		return decodeArraySlice(t, input[32:], length)
+		return decodeArraySlice(t, input[w:], length, w, opts)
 
 	case KindArray:
-		return decodeArraySlice(t, input, t.size)
+		return decodeArraySlice(t, input, t.size, w, opts)
 	}
 
 	var val interface{}
 	switch t.kind {
 	case KindBool:
-		val, err = decodeBool(data)
+		val, err = decodeBool(data, opts)
 
 	case KindInt, KindUInt:
 		val = readInteger(t, data)
+		if opts.EnumNames && t.enum != nil {
+			val, err = decodeEnumName(t, val)
+		}
 
 	case KindString:
-		val = string(input[32 : 32+length])
+		val = string(input[w : w+length])
 
 	case KindBytes:
-		val = input[32 : 32+length]
+		val = input[w : w+length]
 
 	case KindAddress:
 		val, err = readAddr(data)
@@ -95,11 +341,14 @@ func decode(t *Type, input []byte) (interface{}, []byte, error) {
 	case KindFunction:
 		val, err = readFunctionType(t, data)
 
+	case KindFixedPoint:
+		val = decodeFixed(t, data)
+
 	default:
 		return nil, nil, fmt.Errorf("decoding not available for type '%s'", t.kind)
 	}
 
-	return val, input[32:], err
+	return val, input[w:], err
 }
 
 var (
@@ -113,10 +362,10 @@ var (
 
 func readAddr(b []byte) (ethgo.Address, error) {
 	res := ethgo.Address{}
-	if len(b) != 32 {
+	if len(b) < 20 {
 		return res, fmt.Errorf("len is not correct")
 	}
-	copy(res[:], b[12:])
+	copy(res[:], b[len(b)-20:])
 	return res, nil
 }
 
@@ -152,10 +401,9 @@ func readInteger(t *Type, b []byte) interface{} {
 			return ret
 		}
 
-		if ret.Cmp(maxInt256) > 0 {
-			ret.Add(maxUint256, big.NewInt(0).Neg(ret))
-			ret.Add(ret, big.NewInt(1))
-			ret.Neg(ret)
+		if ret.Bit(len(b)*8-1) == 1 {
+			// two's complement with the size of the word
+			ret.Sub(ret, new(big.Int).Lsh(one, uint(len(b)*8)))
 		}
 		return ret
 	}
@@ -163,8 +411,8 @@ func readInteger(t *Type, b []byte) interface{} {
 
 func readFunctionType(t *Type, word []byte) ([24]byte, error) {
 	res := [24]byte{}
-	if !allZeros(word[24:32]) {
-		return res, fmt.Errorf("function type expects the last 8 bytes to be empty but found: %b", word[24:32])
+	if !allZeros(word[24:]) {
+		return res, fmt.Errorf("function type expects the last %d bytes to be empty but found: %b", len(word)-24, word[24:])
 	}
 	copy(res[:], word[0:24])
 	return res, nil
@@ -176,26 +424,26 @@ func readFixedBytes(t *Type, word []byte) (interface{}, error) {
 	return array.Interface(), nil
 }
 
-func decodeTuple(t *Type, data []byte) (interface{}, []byte, error) {
+func decodeTuple(t *Type, data []byte, w int, opts *DecodeOptions) (interface{}, []byte, error) {
 	res := make(map[string]interface{})
 
 	orig := data
 	origLen := len(orig)
 	for indx, arg := range t.tuple {
-		if len(data) < 32 {
+		if len(data) < w {
 			return nil, nil, fmt.Errorf("incorrect length")
 		}
 
 		entry := data
 		if arg.Elem.isDynamicType() {
-			offset, err := readOffset(data, origLen)
+			offset, err := readOffset(data, origLen, w)
 			if err != nil {
 				return nil, nil, err
 			}
 			entry = orig[offset:]
 		}
 
-		val, tail, err := decode(arg.Elem, entry)
+		val, tail, err := decode(arg.Elem, entry, w, opts)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -203,7 +451,7 @@ func decodeTuple(t *Type, data []byte) (interface{}, []byte, error) {
 		if !arg.Elem.isDynamicType() {
 			data = tail
 		} else {
-			data = data[32:]
+			data = data[w:]
 		}
 
 		name := arg.Name
@@ -219,19 +467,24 @@ func decodeTuple(t *Type, data []byte) (interface{}, []byte, error) {
 	return res, data, nil
 }
 
-func decodeArraySlice(t *Type, data []byte, size int) (interface{}, []byte, error) {
+func decodeArraySlice(t *Type, data []byte, size int, w int, opts *DecodeOptions) (interface{}, []byte, error) {
 	if size < 0 {
 		return nil, nil, fmt.Errorf("size is lower than zero")
 	}
-	if 32*size > len(data) {
+	if w*size > len(data) {
 		return nil, nil, fmt.Errorf("size is too big")
 	}
 
+	typ := t.t
+	if opts.EnumNames {
+		typ = enumNamesGoType(t)
+	}
+
 	var res reflect.Value
 	if t.kind == KindSlice {
-		res = reflect.MakeSlice(t.t, size, size)
+		res = reflect.MakeSlice(typ, size, size)
 	} else if t.kind == KindArray {
-		res = reflect.New(t.t).Elem()
+		res = reflect.New(typ).Elem()
 	}
 
 	orig := data
@@ -239,20 +492,20 @@ func decodeArraySlice(t *Type, data []byte, size int) (interface{}, []byte, erro
 	for indx := 0; indx < size; indx++ {
 		isDynamic := t.elem.isDynamicType()
 
-		if len(data) < 32 {
+		if len(data) < w {
 			return nil, nil, fmt.Errorf("incorrect length")
 		}
 
 		entry := data
 		if isDynamic {
-			offset, err := readOffset(data, origLen)
+			offset, err := readOffset(data, origLen, w)
 			if err != nil {
 				return nil, nil, err
 			}
 			entry = orig[offset:]
 		}
 
-		val, tail, err := decode(t.elem, entry)
+		val, tail, err := decode(t.elem, entry, w, opts)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -260,26 +513,42 @@ func decodeArraySlice(t *Type, data []byte, size int) (interface{}, []byte, erro
 		if !isDynamic {
 			data = tail
 		} else {
-			data = data[32:]
+			data = data[w:]
 		}
 		res.Index(indx).Set(reflect.ValueOf(val))
 	}
 	return res.Interface(), data, nil
 }
 
-func decodeBool(data []byte) (interface{}, error) {
-	switch data[31] {
+func decodeBool(data []byte, opts *DecodeOptions) (interface{}, error) {
+	switch data[len(data)-1] {
 	case 0:
+		if opts.LenientBool && !isZero(data) {
+			return true, nil
+		}
 		return false, nil
 	case 1:
 		return true, nil
 	default:
+		if opts.LenientBool {
+			return true, nil
+		}
 		return false, fmt.Errorf("bad boolean")
 	}
 }
 
-func readOffset(data []byte, len int) (int, error) {
-	offsetBig := big.NewInt(0).SetBytes(data[0:32])
+// isZero returns whether all the bytes are zero
+func isZero(data []byte) bool {
+	for _, b := range data {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func readOffset(data []byte, len int, w int) (int, error) {
+	offsetBig := big.NewInt(0).SetBytes(data[0:w])
 	if offsetBig.BitLen() > 63 {
 		return 0, fmt.Errorf("offset larger than int64: %v", offsetBig.Int64())
 	}
@@ -290,8 +559,8 @@ func readOffset(data []byte, len int) (int, error) {
 	return offset, nil
 }
 
-func readLength(data []byte) (int, error) {
-	lengthBig := big.NewInt(0).SetBytes(data[0:32])
+func readLength(data []byte, w int) (int, error) {
+	lengthBig := big.NewInt(0).SetBytes(data[0:w])
 	if lengthBig.BitLen() > 63 {
 		return 0, fmt.Errorf("length larger than int64: %v", lengthBig.Int64())
 	}
@@ -299,7 +568,7 @@ func readLength(data []byte) (int, error) {
 
 	// if we trim the length in the data there should be enough
 	// bytes to cover the length
-	if length > len(data)-32 {
+	if length > len(data)-w {
 		return 0, fmt.Errorf("length insufficient %v require %v", len(data), length)
 	}
 	return length, nil
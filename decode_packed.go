@@ -2,77 +2,399 @@
 package abi
 
 import (
-	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
 	"strconv"
+
 	"github.com/umbracle/ethgo"
 )
 
-// Decode decodes the input with a given type
+// DecodeOptions are the options to customize the decoding. The packed
+// decoding uses all of them except WordSize, while the standard decoding
+// (DecodeWithOptions) only uses WordSize, LenientBool and EnumNames
+type DecodeOptions struct {
+	// Strict returns an error if there are bytes left in the input
+	// after decoding the type, including the bytes at the end of a
+	// slice that do not fill a whole element
+	Strict bool
+
+	// LenientBool decodes any nonzero byte (or word in the standard
+	// encoding) as true instead of returning an error for values other
+	// than 0 and 1
+	LenientBool bool
+
+	// CopyBytes returns the bytes values as a copy of the input. By
+	// default they are sub-slices that share the memory of the input
+	// and change if the input buffer is modified
+	CopyBytes bool
+
+	// TightArrays decodes the array elements without padding. By default
+	// the elementary values of arrays are padded to 32 bytes like in the
+	// abi.encodePacked function of solidity
+	TightArrays bool
+
+	// AddressFormat is the go type of the decoded addresses, ethgo.Address
+	// by default. Standard mode ignores it
+	AddressFormat AddressFormat
+
+	// EnumNames decodes the values of the enum types as the names of
+	// the values instead of their numbers
+	EnumNames bool
+
+	// Uint256 decodes the unsigned integers of more than 64 bits as
+	// *uint256.Int instead of *big.Int. Standard mode ignores it
+	Uint256 bool
+
+	// WordSize is the size in bytes of the words in the standard
+	// encoding (DecodeWithOptions), 32 by default. Packed mode ignores it
+	WordSize int
+}
+
+// AddressFormat is the go type of the addresses decoded in packed mode
+type AddressFormat int
+
+const (
+	// AddressFormatEthgo decodes the addresses as ethgo.Address
+	AddressFormatEthgo AddressFormat = iota
+
+	// AddressFormatBytes decodes the addresses as [20]byte
+	AddressFormatBytes
+
+	// AddressFormatString decodes the addresses as EIP-55 checksum strings
+	AddressFormatString
+)
+
+var addressBytesT = reflect.TypeOf([20]byte{})
+
+// formatAddress returns the address with the go type of the format
+func formatAddress(addr ethgo.Address, format AddressFormat) interface{} {
+	switch format {
+	case AddressFormatBytes:
+		return [20]byte(addr)
+	case AddressFormatString:
+		return addr.String()
+	default:
+		return addr
+	}
+}
+
+// Decode decodes the input with a given type. Bytes values are returned
+// as sub-slices of the input without copying them (see CopyBytes).
+// Packed data has no lengths, so dynamic values (bytes, string and
+// slices) take the rest of the input and are only supported as the last
+// element of a tuple, after static values with a known width like the
+// 20 bytes of an address
 func DecodePacked(t *Type, input []byte) (interface{}, error) {
+	return DecodePackedWithOptions(t, input, nil)
+}
+
+// DecodePackedWithOptions decodes the input with a given type and options
+func DecodePackedWithOptions(t *Type, input []byte, opts *DecodeOptions) (interface{}, error) {
+	if opts == nil {
+		opts = &DecodeOptions{}
+	}
+	if err := ValidatePackedSchema(t); err != nil {
+		return nil, err
+	}
+	if len(input) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+	val, tail, err := decodePacked(t, input, opts)
+	if err != nil {
+		return nil, withOffset(err, len(input))
+	}
+	if opts.Strict && len(tail) != 0 {
+		return nil, fmt.Errorf("%d bytes left after decoding type '%s'", len(tail), t.String())
+	}
+	return val, nil
+}
+
+// DecodePackedFromHex decodes a hex string, with or without the 0x
+// prefix, with a given type
+func DecodePackedFromHex(t *Type, input string) (interface{}, error) {
+	buf, err := decodeHex(input)
+	if err != nil {
+		return nil, err
+	}
+	return DecodePacked(t, buf)
+}
+
+// DecodePackedSkip drops the first skip bytes of the input (i.e. a
+// version prefix) and decodes the rest with a given type
+func DecodePackedSkip(t *Type, input []byte, skip int) (interface{}, error) {
+	if skip < 0 || skip > len(input) {
+		return nil, fmt.Errorf("cannot skip %d bytes of an input with %d", skip, len(input))
+	}
+	return DecodePacked(t, input[skip:])
+}
+
+// MinDecodeLen returns the minimum number of bytes required to decode the
+// type in packed mode. The second value is true if the length is exact
+// (the type is static) and false if it is only a lower bound
+func MinDecodeLen(t *Type) (int, bool) {
+	if size, ok := t.ByteSize(); ok {
+		return size, true
+	}
+
+	switch t.Kind() {
+	case KindArray:
+		size, _ := MinDecodeLen(t.Elem())
+		return t.Size() * size, false
+
+	case KindTuple:
+		total := 0
+		for _, elem := range t.TupleElems() {
+			size, _ := MinDecodeLen(elem.Elem)
+			total += size
+		}
+		return total, false
+
+	default:
+		// bytes, strings and slices can be empty
+		return 0, false
+	}
+}
+
+// DecodeMinimalInt decodes an integer encoded with EncodeMinimalInt
+func DecodeMinimalInt(b []byte, signed bool) *big.Int {
+	res := new(big.Int).SetBytes(b)
+	if signed && len(b) > 0 && b[0]&0x80 != 0 {
+		res.Sub(res, new(big.Int).Lsh(one, uint(len(b)*8)))
+	}
+	return res
+}
+
+// DecodePackedRaw decodes the input with a tuple type and returns the
+// raw packed bytes consumed by each of the tuple elements
+func DecodePackedRaw(t *Type, input []byte) (map[string][]byte, error) {
+	if t.Kind() != KindTuple {
+		return nil, fmt.Errorf("expected a tuple type but found %s", t.String())
+	}
+	if err := ValidatePackedSchema(t); err != nil {
+		return nil, err
+	}
+	if len(input) == 0 {
+		return nil, fmt.Errorf("empty input")
+	}
+
+	opts := &DecodeOptions{}
+	total := len(input)
+	res := make(map[string][]byte)
+	for indx, arg := range t.TupleElems() {
+		_, tail, err := decodePacked(arg.Elem, input, opts)
+		if err != nil {
+			return nil, withOffset(err, total)
+		}
+
+		name := arg.Name
+		if name == "" {
+			name = strconv.Itoa(indx)
+		}
+		if _, ok := res[name]; ok {
+			return nil, fmt.Errorf("tuple with repeated values")
+		}
+		res[name] = input[:len(input)-len(tail)]
+		input = tail
+	}
+	return res, nil
+}
+
+// DecodePackedInto decodes the input with a type into the out param. Struct
+// fields are matched with the 'abi' tag or the field name and fields that
+// implement sql.Scanner are scanned from the decoded value
+func DecodePackedInto(t *Type, input []byte, out interface{}) error {
+	val, err := DecodePacked(t, input)
+	if err != nil {
+		return err
+	}
+	return decodeInto(val, out)
+}
+
+// DecodePackedFlat decodes the types one after the other from the input
+// and returns the values in order. Only the last type can be dynamic
+// since the length of the dynamic values is the rest of the input
+func DecodePackedFlat(types []*Type, input []byte) ([]interface{}, error) {
+	if err := checkPackedFlat(types); err != nil {
+		return nil, err
+	}
 	if len(input) == 0 {
 		return nil, fmt.Errorf("empty input")
 	}
-	val, _, err := decodePacked(t, input)
-	return val, err
+	res, _, err := decodePackedList(types, input, &DecodeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
 }
 
-func decodePacked(t *Type, input []byte) (interface{}, []byte, error) {
+// DecodePackedValues decodes the values encoded with EncodePackedValues.
+// Like DecodePackedFlat only the last type can be dynamic, but the whole
+// input has to be consumed by the types
+func DecodePackedValues(types []*Type, input []byte) ([]interface{}, error) {
+	if err := checkPackedFlat(types); err != nil {
+		return nil, err
+	}
+	res, tail, err := decodePackedList(types, input, &DecodeOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if len(tail) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after decoding %d values", len(tail), len(types))
+	}
+	return res, nil
+}
+
+// checkPackedFlat checks that only the last type of the list is dynamic
+func checkPackedFlat(types []*Type) error {
+	for i, t := range types {
+		if _, ok := t.ByteSize(); !ok && i != len(types)-1 {
+			return fmt.Errorf("dynamic type '%s' at position %d, only the last type can be dynamic", t.String(), i)
+		}
+		if err := ValidatePackedSchema(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidatePackedSchema checks that the values of the type can be split
+// when decoding in packed mode. Dynamic values take the rest of the input
+// so they can only be the last element of a tuple and never the elements
+// of an array. The error has the path of the offending element
+func ValidatePackedSchema(t *Type) error {
+	switch t.Kind() {
+	case KindTuple:
+		elems := t.TupleElems()
+		for indx, arg := range elems {
+			if _, ok := packedByteSize(arg.Elem, true); !ok && indx != len(elems)-1 {
+				err := &schemaError{fmt.Sprintf("dynamic type '%s' at position %d, only the last element of a tuple can be dynamic", arg.Elem.String(), indx)}
+				return withPath(newABIError("decode", arg.Elem, err), tupleElemName(arg, indx))
+			}
+			if err := ValidatePackedSchema(arg.Elem); err != nil {
+				return withPath(err, tupleElemName(arg, indx))
+			}
+		}
+
+	case KindSlice, KindArray:
+		if _, ok := packedByteSize(t.Elem(), true); !ok {
+			// the packed elements have no length to split them
+			return newABIError("decode", t, &schemaError{"packed decode unsupported for dynamic element arrays"})
+		}
+	}
+	return nil
+}
+
+// DecodePackedAs decodes the input with a given type into a new value
+// of type T (i.e. a struct for tuple types) like DecodePackedInto
+func DecodePackedAs[T any](t *Type, input []byte) (T, error) {
+	var res T
+	if err := DecodePackedInto(t, input, &res); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+// decodePackedList decodes the types one after the other from the input
+func decodePackedList(types []*Type, input []byte, opts *DecodeOptions) ([]interface{}, []byte, error) {
+	total := len(input)
+	res := make([]interface{}, len(types))
+	for i, t := range types {
+		val, tail, err := decodePacked(t, input, opts)
+		if err != nil {
+			return nil, nil, withOffset(err, total)
+		}
+		res[i] = val
+		input = tail
+	}
+	return res, input, nil
+}
+
+func decodePacked(t *Type, input []byte, opts *DecodeOptions) (interface{}, []byte, error) {
+	val, tail, err := decodePackedValue(t, input, opts)
+	if err != nil {
+		return nil, nil, newDecodeError(t, err, len(input))
+	}
+	return val, tail, nil
+}
+
+func decodePackedValue(t *Type, input []byte, opts *DecodeOptions) (interface{}, []byte, error) {
 	var err error
 	var length int
 
-
 	switch t.Kind() {
 	case KindSlice, KindBytes, KindString:
 		length = len(input)
-	case KindBool:
-		length = 1
-	case KindInt, KindUInt:
-		length = t.Size()/8
 	default:
-		length = t.Size()
+		// the whole length of static types, arrays and tuples included
+		length, _ = packedByteSize(t, opts.TightArrays)
 	}
 	if length > len(input) {
-		return nil, nil, fmt.Errorf("Input kind '%s' requires length %d, but input has %d", t.Kind(), length, len(input))
+		return nil, nil, &lengthError{
+			msg:       fmt.Sprintf("Input kind '%s' requires length %d, but input has %d", t.Kind(), length, len(input)),
+			expected:  length,
+			available: len(input),
+		}
 	}
 
 	switch t.Kind() {
 	case KindTuple:
-		return decodeTuplePacked(t, input)
+		return decodeTuplePacked(t, input, opts)
 
 	case KindSlice:
-		eSize := t.Elem().Size()
-		if eSize == 0 {
-			eSize = length
+		eSize, ok := packedElemSize(t.Elem(), opts.TightArrays)
+		if !ok {
+			return nil, nil, &schemaError{"packed decode unsupported for dynamic element arrays"}
 		}
-		if t.Elem().Kind() == KindInt || t.Elem().Kind() == KindUInt {
-			eSize = eSize/8
+		size := 0
+		if eSize != 0 {
+			size = length / eSize
+			if rest := length % eSize; opts.Strict && rest != 0 {
+				return nil, nil, fmt.Errorf("%d bytes left after the %d elements of the slice", rest, size)
+			}
 		}
-		return decodeArraySlicePacked(t, input, length/eSize)
+		return decodeArraySlicePacked(t, input, size, opts)
 
 	case KindArray:
-		return decodeArraySlicePacked(t, input, t.Size())
+		return decodeArraySlicePacked(t, input, t.Size(), opts)
+	}
+
+	if fn, ok := getDecodeConverter(t.Kind()); ok {
+		val, err := fn(input[:length])
+		return val, input[length:], err
 	}
 
-	
 	var val interface{}
 	switch t.Kind() {
 	case KindBool:
-		val, err = decodeBoolPacked(input[:length])
+		val, err = decodeBoolPacked(input[:length], opts)
 
 	case KindInt, KindUInt:
-		val = readIntegerPacked(t, input[:length])
+		if opts.Uint256 && t.Kind() == KindUInt && t.Size() > 64 {
+			val = readUint256(input[:length])
+		} else {
+			val = readIntegerPacked(t, input[:length], opts)
+		}
+		if opts.EnumNames && t.enum != nil {
+			val, err = decodeEnumName(t, val)
+		}
 
 	case KindString: // only last bytes
 		val = string(input)
 
 	case KindBytes: // only last bytes
-		val = input
+		if opts.CopyBytes {
+			val = append([]byte{}, input...)
+		} else {
+			val = input
+		}
 
 	case KindAddress:
-		val, err = readAddrPacked(input[:length])
+		var addr ethgo.Address
+		if addr, err = readAddrPacked(input[:length]); err == nil {
+			val = formatAddress(addr, opts.AddressFormat)
+		}
 
 	case KindFixedBytes:
 		val, err = readFixedBytesPacked(t, input[:length])
@@ -80,6 +402,9 @@ func decodePacked(t *Type, input []byte) (interface{}, []byte, error) {
 	case KindFunction:
 		val, err = readFunctionTypePacked(t, input[:length])
 
+	case KindFixedPoint:
+		val = decodeFixed(t, input[:length])
+
 	default:
 		return nil, nil, fmt.Errorf("decoding not available for type '%s'", t.Kind())
 	}
@@ -96,45 +421,114 @@ func readAddrPacked(b []byte) (ethgo.Address, error) {
 	return res, nil
 }
 
-func readIntegerPacked(t *Type, b []byte) interface{} {
-	switch t.GoType().Kind() {
-	case reflect.Uint8:
-		return b[0]
-
-	case reflect.Uint16:
-		return binary.BigEndian.Uint16(b)
+// readIntegerPacked reads an integer of the size of the type as the go
+// type returned by packedIntType
+func readIntegerPacked(t *Type, b []byte, opts *DecodeOptions) interface{} {
+	typ := packedIntType(t, opts)
+	if typ == bigIntT {
+		ret := new(big.Int).SetBytes(b)
+		if t.Kind() == KindUInt {
+			return ret
+		}
 
-	case reflect.Uint32:
-		return binary.BigEndian.Uint32(b)
+		// two's complement, negative numbers have the highest bit set
+		if ret.Bit(t.Size()-1) == 1 {
+			ret.Sub(ret, new(big.Int).Lsh(one, uint(t.Size())))
+		}
+		return ret
+	}
 
-	case reflect.Uint64:
-		return binary.BigEndian.Uint64(b)
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	if t.Kind() == KindUInt {
+		switch typ.Kind() {
+		case reflect.Uint8:
+			return uint8(u)
+		case reflect.Uint16:
+			return uint16(u)
+		case reflect.Uint32:
+			return uint32(u)
+		default:
+			return u
+		}
+	}
 
+	// sign extend from the highest bit of the type
+	shift := uint(64 - t.Size())
+	n := int64(u<<shift) >> shift
+	switch typ.Kind() {
 	case reflect.Int8:
-		return int8(b[0])
-
+		return int8(n)
 	case reflect.Int16:
-		return int16(binary.BigEndian.Uint16(b))
-
+		return int16(n)
 	case reflect.Int32:
-		return int32(binary.BigEndian.Uint32(b))
+		return int32(n)
+	default:
+		return n
+	}
+}
 
-	case reflect.Int64:
-		return int64(binary.BigEndian.Uint64(b))
+// packedGoType returns the go type of the values decoded in packed mode
+// with the options, which can be nil
+func packedGoType(t *Type, opts *DecodeOptions) reflect.Type {
+	switch t.Kind() {
+	case KindInt, KindUInt:
+		if opts != nil && opts.EnumNames && t.enum != nil {
+			return stringT
+		}
+		return packedIntType(t, opts)
 
-	default:
-		ret := new(big.Int).SetBytes(b)
-		if t.Kind() == KindUInt {
-			return ret
+	case KindAddress:
+		if opts != nil && opts.AddressFormat == AddressFormatBytes {
+			return addressBytesT
+		} else if opts != nil && opts.AddressFormat == AddressFormatString {
+			return stringT
 		}
+		return t.GoType()
+
+	case KindSlice:
+		return reflect.SliceOf(packedGoType(t.Elem(), opts))
+
+	case KindArray:
+		return reflect.ArrayOf(t.Size(), packedGoType(t.Elem(), opts))
 
-		if ret.Cmp(maxInt256) > 0 {
-			ret.Add(maxUint256, big.NewInt(0).Neg(ret))
-			ret.Add(ret, big.NewInt(1))
-			ret.Neg(ret)
+	default:
+		return t.GoType()
+	}
+}
+
+// packedIntType returns the go type of the integers decoded in packed
+// mode, which maps the integers of up to 64 bits to the smallest native
+// type that fits them (i.e. uint24 as uint32 and int40 as int64). The
+// options can be nil
+func packedIntType(t *Type, opts *DecodeOptions) reflect.Type {
+	if t.Size() > 64 {
+		if opts != nil && opts.Uint256 && t.Kind() == KindUInt {
+			return uint256T
 		}
-		return ret
+		return t.GoType()
 	}
+	signed := t.Kind() == KindInt
+	switch {
+	case t.Size() <= 8:
+		return intGoType(signed, int8T, uint8T)
+	case t.Size() <= 16:
+		return intGoType(signed, int16T, uint16T)
+	case t.Size() <= 32:
+		return intGoType(signed, int32T, uint32T)
+	default:
+		return intGoType(signed, int64T, uint64T)
+	}
+}
+
+// intGoType returns the signed or the unsigned integer type
+func intGoType(signed bool, i, u reflect.Type) reflect.Type {
+	if signed {
+		return i
+	}
+	return u
 }
 
 func readFunctionTypePacked(t *Type, word []byte) ([24]byte, error) {
@@ -144,21 +538,28 @@ func readFunctionTypePacked(t *Type, word []byte) ([24]byte, error) {
 }
 
 func readFixedBytesPacked(t *Type, word []byte) (interface{}, error) {
+	if t.Size() == 32 {
+		var res [32]byte
+		copy(res[:], word)
+		return res, nil
+	}
 	array := reflect.New(t.GoType()).Elem()
 	reflect.Copy(array, reflect.ValueOf(word[0:t.Size()]))
 	return array.Interface(), nil
 }
 
-func decodeTuplePacked(t *Type, data []byte) (interface{}, []byte, error) {
+// decodeTuplePacked decodes the elements in order, each static element
+// advances by its width and a dynamic element takes the rest of the data
+func decodeTuplePacked(t *Type, data []byte, opts *DecodeOptions) (interface{}, []byte, error) {
 	res := make(map[string]interface{})
 
 	for indx, arg := range t.TupleElems() {
 
 		entry := data
 
-		val, tail, err := decodePacked(arg.Elem, entry)
+		val, tail, err := decodePacked(arg.Elem, entry, opts)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, withPath(err, tupleElemName(arg, indx))
 		}
 
 		data = tail
@@ -176,26 +577,34 @@ func decodeTuplePacked(t *Type, data []byte) (interface{}, []byte, error) {
 	return res, data, nil
 }
 
-func decodeArraySlicePacked(t *Type, data []byte, size int) (interface{}, []byte, error) {
+func decodeArraySlicePacked(t *Type, data []byte, size int, opts *DecodeOptions) (interface{}, []byte, error) {
+	elemSize, ok := packedElemSize(t.Elem(), opts.TightArrays)
+	if !ok {
+		// the packed elements have no length to split them
+		return nil, nil, &schemaError{"packed decode unsupported for dynamic element arrays"}
+	}
 	if size < 0 {
 		return nil, nil, fmt.Errorf("size is lower than zero")
 	}
-	if t.Elem().Size()/8*size > len(data) {
+	if elemSize*size > len(data) {
 		return nil, nil, fmt.Errorf("size is too big")
 	}
 
 	var res reflect.Value
-	if t.Kind() == KindSlice {
-		res = reflect.MakeSlice(t.GoType(), size, size)
+	if hasDecodeConverter(t.Elem()) {
+		// the converted values may have any go type
+		res = reflect.MakeSlice(reflect.TypeOf([]interface{}{}), size, size)
+	} else if t.Kind() == KindSlice {
+		res = reflect.MakeSlice(packedGoType(t, opts), size, size)
 	} else if t.Kind() == KindArray {
-		res = reflect.New(t.GoType()).Elem()
+		res = reflect.New(packedGoType(t, opts)).Elem()
 	}
 
 	for indx := 0; indx < size; indx++ {
 		entry := data
-		val, tail, err := decodePacked(t.Elem(), entry)
+		val, tail, err := decodeArrayElemPacked(t.Elem(), entry, opts)
 		if err != nil {
-			return nil, nil, err
+			return nil, nil, withPath(err, fmt.Sprintf("[%d]", indx))
 		}
 		data = tail
 		res.Index(indx).Set(reflect.ValueOf(val))
@@ -203,13 +612,54 @@ func decodeArraySlicePacked(t *Type, data []byte, size int) (interface{}, []byte
 	return res.Interface(), data, nil
 }
 
-func decodeBoolPacked(data []byte) (interface{}, error) {
+// decodeArrayElemPacked decodes a single element of a packed array. The
+// elementary values are padded to 32 bytes unless the arrays are tight
+func decodeArrayElemPacked(t *Type, data []byte, opts *DecodeOptions) (interface{}, []byte, error) {
+	if opts.TightArrays {
+		return decodePacked(t, data, opts)
+	}
+	switch t.Kind() {
+	case KindBool, KindInt, KindUInt, KindAddress, KindFunction, KindFixedBytes, KindFixedPoint:
+	default:
+		return decodePacked(t, data, opts)
+	}
+
+	if len(data) < 32 {
+		err := &lengthError{
+			msg:       fmt.Sprintf("padded %s element requires length 32, but input has %d", t.String(), len(data)),
+			expected:  32,
+			available: len(data),
+		}
+		return nil, nil, newDecodeError(t, err, len(data))
+	}
+	size, _ := t.ByteSize()
+	word := data[32-size : 32]
+	if t.Kind() == KindFixedBytes || t.Kind() == KindFunction {
+		// fixed bytes are padded on the right
+		word = data[:size]
+	}
+	val, _, err := decodePacked(t, word, opts)
+	if err != nil {
+		// the offset of the element is the start of the padded word
+		var abiErr *ABIError
+		if errors.As(err, &abiErr) {
+			abiErr.rest = len(data)
+		}
+		return nil, nil, err
+	}
+	return val, data[32:], nil
+}
+
+func decodeBoolPacked(data []byte, opts *DecodeOptions) (interface{}, error) {
 	switch data[0] {
 	case 0:
 		return false, nil
 	case 1:
 		return true, nil
 	default:
+		if opts.LenientBool {
+			return true, nil
+		}
 		return false, fmt.Errorf("bad boolean")
 	}
 }
@@ -0,0 +1,531 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestDecodePacked_Strict(t *testing.T) {
+	typ := MustNewType("uint8[3]")
+	input, err := EncodePacked([3]uint8{1, 2, 3}, typ)
+	require.NoError(t, err)
+	input = append(input, 0x4)
+
+	// by default the trailing bytes are ignored
+	res, err := DecodePacked(typ, input)
+	require.NoError(t, err)
+	require.Equal(t, [3]uint8{1, 2, 3}, res)
+
+	_, err = DecodePackedWithOptions(typ, input, &DecodeOptions{Strict: true})
+	require.Error(t, err)
+
+	res, err = DecodePackedWithOptions(typ, input[:96], &DecodeOptions{Strict: true})
+	require.NoError(t, err)
+	require.Equal(t, [3]uint8{1, 2, 3}, res)
+
+	// the partial elements of nested slices are located
+	_, err = DecodePackedWithOptions(MustNewType("tuple(uint8 a, uint16[] b)"), []byte{0x1, 0x0, 0x2, 0x3}, &DecodeOptions{Strict: true, TightArrays: true})
+	require.EqualError(t, err, "b: 1 bytes left after the 1 elements of the slice")
+}
+
+func TestDecodePacked_SignedBoundaries(t *testing.T) {
+	minInt256 := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))
+
+	cases := []struct {
+		typ      string
+		input    string
+		expected *big.Int
+	}{
+		{"int256", "0x8000000000000000000000000000000000000000000000000000000000000000", minInt256},
+		{"int256", "0x7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", maxInt256},
+		{"int256", "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", big.NewInt(-1)},
+		{"int72", "0x800000000000000000", new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 71))},
+		{"int72", "0x7fffffffffffffffff", new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 71), big.NewInt(1))},
+		{"int72", "0xffffffffffffffffff", big.NewInt(-1)},
+		{"int128", "0xffffffffffffffffffffffffffffffff", big.NewInt(-1)},
+	}
+
+	for _, c := range cases {
+		res, err := DecodePacked(MustNewType(c.typ), mustDecodeHex(c.input))
+		require.NoError(t, err)
+		require.Equal(t, 0, c.expected.Cmp(res.(*big.Int)), c.input)
+	}
+
+	// round trip the boundaries through the encoder
+	for _, num := range []*big.Int{minInt256, maxInt256} {
+		buf, err := EncodePacked(num, MustNewType("int256"))
+		require.NoError(t, err)
+
+		res, err := DecodePacked(MustNewType("int256"), buf)
+		require.NoError(t, err)
+		require.Equal(t, 0, num.Cmp(res.(*big.Int)))
+	}
+}
+
+type scannerInt struct {
+	Val   int64
+	Valid bool
+}
+
+func (s *scannerInt) Scan(src interface{}) error {
+	val, ok := src.(int64)
+	if !ok {
+		return fmt.Errorf("unexpected type %T", src)
+	}
+	s.Val, s.Valid = val, true
+	return nil
+}
+
+func TestDecodePacked_NativeIntegers(t *testing.T) {
+	cases := []struct {
+		typ      string
+		input    string
+		expected interface{}
+	}{
+		{"uint8", "0xff", uint8(255)},
+		{"uint24", "0xffffff", uint32(16777215)},
+		{"uint40", "0x0100000000", uint64(4294967296)},
+		{"uint64", "0xffffffffffffffff", uint64(18446744073709551615)},
+		{"int24", "0x800000", int32(-8388608)},
+		{"int24", "0x7fffff", int32(8388607)},
+		{"int24", "0xffffff", int32(-1)},
+		{"int48", "0xfffffffffffe", int64(-2)},
+		{"int56", "0x7fffffffffffff", int64(36028797018963967)},
+		{"int64", "0x8000000000000000", int64(-9223372036854775808)},
+		{"uint72", "0x010000000000000000", new(big.Int).Lsh(big.NewInt(1), 64)},
+	}
+
+	for _, c := range cases {
+		res, err := DecodePacked(MustNewType(c.typ), mustDecodeHex(c.input))
+		require.NoError(t, err)
+		require.Equal(t, c.expected, res, c.typ)
+	}
+
+	// the arrays use the native types for the elements
+	typ := MustNewType("int24[2]")
+	input, err := EncodePacked([2]int32{-1, 2}, typ)
+	require.NoError(t, err)
+
+	res, err := DecodePacked(typ, input)
+	require.NoError(t, err)
+	require.Equal(t, [2]int32{-1, 2}, res)
+}
+
+func TestDecodePackedInto_Scanner(t *testing.T) {
+	typ := MustNewType("tuple(uint64 a, uint256 b, uint8 c)")
+
+	input, err := EncodePacked(map[string]interface{}{
+		"a": uint64(1),
+		"b": big.NewInt(2),
+		"c": uint8(3),
+	}, typ)
+	require.NoError(t, err)
+
+	var out struct {
+		A scannerInt
+		B *scannerInt
+		C uint8 `abi:"c"`
+	}
+	require.NoError(t, DecodePackedInto(typ, input, &out))
+	require.Equal(t, scannerInt{Val: 1, Valid: true}, out.A)
+	require.Equal(t, &scannerInt{Val: 2, Valid: true}, out.B)
+	require.Equal(t, uint8(3), out.C)
+}
+
+func TestDecodePackedInto_BigInt(t *testing.T) {
+	typ := MustNewType("tuple(address to, uint256 amount, int256 delta, uint256 total)")
+
+	input, err := EncodePacked(map[string]interface{}{
+		"to":     ethgo.Address{0x1},
+		"amount": big.NewInt(1000),
+		"delta":  big.NewInt(-5),
+		"total":  big.NewInt(7),
+	}, typ)
+	require.NoError(t, err)
+
+	var out struct {
+		Recipient ethgo.Address `abi:"to"`
+		Amount    uint64
+		Delta     int32
+		Total     *big.Int
+	}
+	require.NoError(t, typ.DecodePackedInto(input, &out))
+	require.Equal(t, ethgo.Address{0x1}, out.Recipient)
+	require.Equal(t, uint64(1000), out.Amount)
+	require.Equal(t, int32(-5), out.Delta)
+	require.Equal(t, big.NewInt(7), out.Total)
+
+	// the values must fit in the fields
+	var small struct {
+		Amount uint8
+	}
+	err = DecodePackedInto(typ, input, &small)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "value 1000 overflows uint8")
+
+	var unsigned struct {
+		Delta uint64
+	}
+	require.Error(t, DecodePackedInto(typ, input, &unsigned))
+}
+
+func TestDecodePackedRaw(t *testing.T) {
+	typ := MustNewType("tuple(address a, uint16 b, bytes3, string c)")
+	input := mustDecodeHex("0x" +
+		"0100000000000000000000000000000000000000" +
+		"0102" +
+		"616263" +
+		"68656c6c6f")
+
+	res, err := DecodePackedRaw(typ, input)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{
+		"a": input[:20],
+		"b": {0x1, 0x2},
+		"2": []byte("abc"),
+		"c": []byte("hello"),
+	}, res)
+
+	_, err = DecodePackedRaw(MustNewType("uint8"), input)
+	require.Error(t, err)
+
+	_, err = DecodePackedRaw(typ, input[:10])
+	require.Error(t, err)
+}
+
+func TestMinDecodeLen(t *testing.T) {
+	size, exact := MinDecodeLen(MustNewType("uint256"))
+	require.Equal(t, 32, size)
+	require.True(t, exact)
+
+	size, exact = MinDecodeLen(MustNewType("string"))
+	require.Equal(t, 0, size)
+	require.False(t, exact)
+
+	size, exact = MinDecodeLen(MustNewType("tuple(address a, uint16 b, bytes c)"))
+	require.Equal(t, 22, size)
+	require.False(t, exact)
+}
+
+func TestDecodePackedFlat(t *testing.T) {
+	types := []*Type{
+		MustNewType("uint64"),
+		MustNewType("address"),
+		MustNewType("bytes32"),
+	}
+	input := mustDecodeHex("0x" +
+		"0000000000000102" +
+		"0100000000000000000000000000000000000000" +
+		"0200000000000000000000000000000000000000000000000000000000000003")
+
+	res, err := DecodePackedFlat(types, input)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		uint64(258),
+		ethgo.Address{0x1},
+		[32]byte{0x2, 31: 0x3},
+	}, res)
+
+	_, err = DecodePackedFlat(types, input[:40])
+	require.Error(t, err)
+
+	// only the last type can be dynamic
+	res, err = DecodePackedFlat([]*Type{MustNewType("uint8"), MustNewType("string")}, []byte{0x1, 'a', 'b'})
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{uint8(1), "ab"}, res)
+
+	_, err = DecodePackedFlat([]*Type{MustNewType("string"), MustNewType("uint8")}, []byte{0x1, 'a', 'b'})
+	require.Error(t, err)
+}
+
+func TestDecodePacked_LenientBool(t *testing.T) {
+	typ := MustNewType("bool")
+	lenient := &DecodeOptions{LenientBool: true}
+
+	cases := []struct {
+		input   byte
+		strict  interface{}
+		lenient interface{}
+	}{
+		{0x00, false, false},
+		{0x01, true, true},
+		{0xff, nil, true},
+	}
+	for _, c := range cases {
+		res, err := DecodePacked(typ, []byte{c.input})
+		if c.strict == nil {
+			require.EqualError(t, err, "bad boolean")
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, c.strict, res)
+		}
+
+		res, err = DecodePackedWithOptions(typ, []byte{c.input}, lenient)
+		require.NoError(t, err)
+		require.Equal(t, c.lenient, res)
+	}
+}
+
+func TestDecodePacked_CopyBytes(t *testing.T) {
+	typ := MustNewType("tuple(uint8 a, bytes b)")
+
+	input := []byte{0x1, 0x2, 0x3}
+	res, err := DecodePacked(typ, input)
+	require.NoError(t, err)
+
+	// by default the value shares the input buffer
+	input[1] = 0xff
+	require.Equal(t, []byte{0xff, 0x3}, res.(map[string]interface{})["b"])
+
+	input = []byte{0x1, 0x2, 0x3}
+	res, err = DecodePackedWithOptions(typ, input, &DecodeOptions{CopyBytes: true})
+	require.NoError(t, err)
+
+	input[1] = 0xff
+	require.Equal(t, []byte{0x2, 0x3}, res.(map[string]interface{})["b"])
+}
+
+func TestDecodePacked_AddressDynamicTail(t *testing.T) {
+	typ := MustNewType("tuple(address owner, string name)")
+
+	addr := ethgo.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	input, err := EncodePacked(map[string]interface{}{
+		"owner": addr,
+		"name":  "hello world",
+	}, typ)
+	require.NoError(t, err)
+	require.Len(t, input, 20+11)
+
+	res, err := DecodePacked(typ, input)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"owner": addr,
+		"name":  "hello world",
+	}, res)
+
+	// an empty tail decodes as an empty string
+	res, err = DecodePacked(typ, addr[:])
+	require.NoError(t, err)
+	require.Equal(t, "", res.(map[string]interface{})["name"])
+}
+
+func TestDecodePacked_DynamicElementArray(t *testing.T) {
+	for _, typ := range []string{"string[3]", "bytes[]", "tuple(uint8,string)[2]"} {
+		_, err := DecodePacked(MustNewType(typ), []byte("abcdefghijk"))
+		require.EqualError(t, err, "packed decode unsupported for dynamic element arrays", typ)
+	}
+}
+
+func TestDecodePackedAs(t *testing.T) {
+	type Transfer struct {
+		To     ethgo.Address
+		Amount *big.Int
+		Memo   string
+	}
+	typ := MustNewType("tuple(address to, uint256 amount, string memo)")
+
+	input, err := EncodePacked(&Transfer{
+		To:     ethgo.Address{0x1},
+		Amount: big.NewInt(100),
+		Memo:   "hello",
+	}, typ)
+	require.NoError(t, err)
+
+	res, err := DecodePackedAs[Transfer](typ, input)
+	require.NoError(t, err)
+	require.Equal(t, Transfer{
+		To:     ethgo.Address{0x1},
+		Amount: big.NewInt(100),
+		Memo:   "hello",
+	}, res)
+
+	_, err = DecodePackedAs[Transfer](typ, input[:10])
+	require.Error(t, err)
+}
+
+func TestDecodePacked_NestedFixedArray(t *testing.T) {
+	typ := MustNewType("uint16[2][3]")
+	value := [3][2]uint16{{1, 2}, {3, 4}, {5, 6}}
+
+	input, err := EncodePacked(value, typ)
+	require.NoError(t, err)
+	require.Len(t, input, 6*32)
+	for i := 0; i < 6; i++ {
+		require.Equal(t, leftPad([]byte{byte(i + 1)}, 32), input[i*32:(i+1)*32])
+	}
+
+	res, err := DecodePacked(typ, input)
+	require.NoError(t, err)
+	require.Equal(t, value, res)
+
+	// the input must have the length of the whole array
+	_, err = DecodePacked(typ, input[:190])
+	require.EqualError(t, err, "Input kind 'Array' requires length 192, but input has 190")
+
+	// inside a tuple the next element starts after the array
+	tuple := MustNewType("tuple(uint16[2][3] a, uint8 b)")
+	res, err = DecodePacked(tuple, append(input, 0x7))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": value, "b": uint8(7)}, res)
+}
+
+func TestDecodePackedSkip(t *testing.T) {
+	typ := MustNewType("tuple(uint8 a, address b)")
+	input := append([]byte{0x1, 0x2}, ethgo.Address{0x3}.Bytes()...)
+
+	res, err := DecodePackedSkip(typ, input, 1)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": uint8(2), "b": ethgo.Address{0x3}}, res)
+
+	_, err = DecodePackedSkip(typ, input, len(input)+1)
+	require.Error(t, err)
+
+	_, err = DecodePackedSkip(typ, input, -1)
+	require.Error(t, err)
+}
+
+func TestDecodePackedValues(t *testing.T) {
+	types := []*Type{MustNewType("address"), MustNewType("uint16"), MustNewType("string")}
+	addr := ethgo.Address{0x1}
+
+	input, err := EncodePackedValues(types, addr, uint16(2), "abc")
+	require.NoError(t, err)
+
+	res, err := DecodePackedValues(types, input)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{addr, uint16(2), "abc"}, res)
+
+	// dynamic types are only allowed at the end
+	_, err = DecodePackedValues([]*Type{MustNewType("string"), MustNewType("uint16")}, input)
+	require.EqualError(t, err, "dynamic type 'string' at position 0, only the last type can be dynamic")
+
+	// the whole input has to be decoded
+	_, err = DecodePackedValues(types[:2], input[:23])
+	require.EqualError(t, err, "1 trailing bytes after decoding 2 values")
+
+	_, err = DecodePackedValues(types[:2], input[:21])
+	require.Error(t, err)
+}
+
+func TestValidatePackedSchema(t *testing.T) {
+	cases := []struct {
+		typ string
+		err string
+	}{
+		{"tuple(address a, uint8[3] b, string c)", ""},
+		{"tuple(uint8 a, tuple(bool b, bytes c) d)", ""},
+		{"tuple(string a, uint8 b)", "a: dynamic type 'string' at position 0, only the last element of a tuple can be dynamic"},
+		{"tuple(uint8 a, tuple(bytes b, bool c) d)", "d.b: dynamic type 'bytes' at position 0, only the last element of a tuple can be dynamic"},
+		{"tuple(uint8 a, tuple(bool b, bytes c) d, uint8 e)", "d: dynamic type 'tuple(bool,bytes)' at position 1, only the last element of a tuple can be dynamic"},
+		{"tuple(uint8 a, string[] b)", "b: packed decode unsupported for dynamic element arrays"},
+	}
+
+	for _, c := range cases {
+		err := ValidatePackedSchema(MustNewType(c.typ))
+		if c.err == "" {
+			require.NoError(t, err, c.typ)
+		} else {
+			require.EqualError(t, err, c.err, c.typ)
+		}
+	}
+
+	// the schema is validated before decoding
+	_, err := DecodePacked(MustNewType("tuple(string a, uint8 b)"), []byte("abc"))
+	require.EqualError(t, err, "a: dynamic type 'string' at position 0, only the last element of a tuple can be dynamic")
+}
+
+func BenchmarkDecodePacked_Tuple(b *testing.B) {
+	typ := MustNewType("tuple(address a, uint256 b, bytes32 c, string e)")
+	input, err := EncodePacked(benchmarkPackedValue(), typ)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodePacked(typ, input); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDecodePackedInto_FastPath(t *testing.T) {
+	addr := ethgo.Address{0x1}
+	hash := ethgo.Hash{0x2}
+
+	var u uint64
+	require.NoError(t, DecodePackedInto(MustNewType("uint64"), []byte{0, 0, 0, 0, 0, 0, 1, 2}, &u))
+	require.Equal(t, uint64(0x102), u)
+
+	var n big.Int
+	require.NoError(t, DecodePackedInto(MustNewType("uint256"), hash[:], &n))
+	require.Equal(t, new(big.Int).SetBytes(hash[:]), &n)
+
+	var np *big.Int
+	require.NoError(t, DecodePackedInto(MustNewType("int256"), hash[:], &np))
+	require.Equal(t, new(big.Int).SetBytes(hash[:]), np)
+
+	var a ethgo.Address
+	require.NoError(t, DecodePackedInto(MustNewType("address"), addr[:], &a))
+	require.Equal(t, addr, a)
+
+	var h ethgo.Hash
+	require.NoError(t, DecodePackedInto(MustNewType("bytes32"), hash[:], &h))
+	require.Equal(t, hash, h)
+
+	var s string
+	require.NoError(t, DecodePackedInto(MustNewType("string"), []byte("abc"), &s))
+	require.Equal(t, "abc", s)
+
+	var b []byte
+	require.NoError(t, DecodePackedInto(MustNewType("bytes"), []byte{0x1}, &b))
+	require.Equal(t, []byte{0x1}, b)
+
+	// other combinations use the reflection path
+	require.NoError(t, DecodePackedInto(MustNewType("uint8"), []byte{0x5}, &u))
+	require.Equal(t, uint64(5), u)
+}
+
+func BenchmarkDecodePackedInto_Hash(b *testing.B) {
+	typ := MustNewType("bytes32")
+	input := make([]byte, 32)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var h ethgo.Hash
+		if err := DecodePackedInto(typ, input, &h); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDecodePacked_AddressFormat(t *testing.T) {
+	addr := ethgo.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+	cases := []struct {
+		format   AddressFormat
+		expected interface{}
+		list     interface{}
+	}{
+		{AddressFormatEthgo, addr, []ethgo.Address{addr, addr}},
+		{AddressFormatBytes, [20]byte(addr), [][20]byte{addr, addr}},
+		{AddressFormatString, addr.String(), []string{addr.String(), addr.String()}},
+	}
+
+	list, err := EncodePacked([]ethgo.Address{addr, addr}, MustNewType("address[]"))
+	require.NoError(t, err)
+
+	for _, c := range cases {
+		opts := &DecodeOptions{AddressFormat: c.format}
+
+		res, err := DecodePackedWithOptions(MustNewType("address"), addr[:], opts)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, res)
+
+		res, err = DecodePackedWithOptions(MustNewType("address[]"), list, opts)
+		require.NoError(t, err)
+		require.Equal(t, c.list, res)
+	}
+}
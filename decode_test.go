@@ -1,14 +1,16 @@
 package abi
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
 )
 
 func TestDecode_BytesBound(t *testing.T) {
 	typ := MustNewType("tuple(string)")
-	decodeTuple(typ, nil) // it should not panic
+	decodeTuple(typ, nil, 32, &DecodeOptions{}) // it should not panic
 }
 
 func TestDecode_DynamicLengthOutOfBounds(t *testing.T) {
@@ -18,3 +20,101 @@ func TestDecode_DynamicLengthOutOfBounds(t *testing.T) {
 	_, err := Decode(typ, input)
 	require.Error(t, err)
 }
+
+func TestDecode_DynamicSlice(t *testing.T) {
+	input := mustDecodeHex("0x" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"0000000000000000000000000000000000000000000000000000000000000003")
+
+	expected := []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}
+
+	// top level slice, the length word comes first
+	val, err := Decode(MustNewType("uint256[]"), input)
+	require.NoError(t, err)
+	require.Equal(t, expected, val)
+
+	// inside a tuple the slice is referenced by its offset
+	offset := mustDecodeHex("0x0000000000000000000000000000000000000000000000000000000000000020")
+	val, err = Decode(MustNewType("tuple(uint256[] a)"), append(offset, input...))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"a": expected}, val)
+}
+
+func TestDecodeReturns(t *testing.T) {
+	input := mustDecodeHex("0x" +
+		"000000000000000000000000000000000000000000000000000000000000002a" +
+		"0000000000000000000000000000000000000000000000000000000000000001")
+
+	res, err := DecodeReturns(MustNewType("tuple(uint256 amount, bool)"), input)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"amount": big.NewInt(42),
+		"1":      true,
+	}, res)
+
+	_, err = DecodeReturns(MustNewType("uint256"), input)
+	require.Error(t, err)
+
+	_, err = DecodeReturns(MustNewType("tuple(uint256, bool)"), nil)
+	require.Error(t, err)
+}
+
+func TestDecodeAs(t *testing.T) {
+	type Transfer struct {
+		To     ethgo.Address
+		Amount *big.Int
+	}
+	typ := MustNewType("tuple(address to, uint256 amount)")
+
+	value := Transfer{To: ethgo.Address{0x1}, Amount: big.NewInt(100)}
+	input, err := EncodeAs(value, typ)
+	require.NoError(t, err)
+
+	res, err := DecodeAs[Transfer](typ, input)
+	require.NoError(t, err)
+	require.Equal(t, value, res)
+
+	amount, err := DecodeAs[uint64](MustNewType("uint256"), input[32:])
+	require.NoError(t, err)
+	require.Equal(t, uint64(100), amount)
+
+	_, err = DecodeAs[Transfer](typ, input[:10])
+	require.Error(t, err)
+
+	_, err = EncodeAs(true, MustNewType("address"))
+	require.EqualError(t, err, "go type bool cannot be encoded as address")
+}
+
+func TestDecode_LenientBool(t *testing.T) {
+	typ := MustNewType("bool")
+	lenient := &DecodeOptions{LenientBool: true}
+
+	word := func(b ...byte) []byte {
+		return leftPad(b, 32)
+	}
+	cases := []struct {
+		input   []byte
+		strict  interface{}
+		lenient interface{}
+	}{
+		{word(0x00), false, false},
+		{word(0x01), true, true},
+		{word(0xff), nil, true},
+		{word(0x01, 0x00), false, true},
+	}
+	for _, c := range cases {
+		res, err := Decode(typ, c.input)
+		if c.strict == nil {
+			require.EqualError(t, err, "bad boolean")
+		} else {
+			require.NoError(t, err)
+			require.Equal(t, c.strict, res)
+		}
+
+		res, err = DecodeWithOptions(typ, c.input, lenient)
+		require.NoError(t, err)
+		require.Equal(t, c.lenient, res)
+	}
+}
@@ -0,0 +1,250 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+)
+
+// TypedDataField is a field of an EIP-712 struct type
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedDataTypes are the struct types of an EIP-712 message indexed by name
+type TypedDataTypes map[string][]TypedDataField
+
+// TypedDataDomain is the EIP-712 domain of a message. Only the fields
+// that are set are part of the domain separator
+type TypedDataDomain struct {
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract *ethgo.Address
+	Salt              *ethgo.Hash
+}
+
+// TypedData is an EIP-712 typed structured data message
+type TypedData struct {
+	Types       TypedDataTypes
+	PrimaryType string
+	Domain      *TypedDataDomain
+	Message     map[string]interface{}
+}
+
+// typedDataDomainName is the name of the struct type of the domain
+const typedDataDomainName = "EIP712Domain"
+
+// Digest returns the EIP-712 digest of the message that is signed:
+// keccak256("\x19\x01" || domainSeparator || hashStruct(message))
+func (t *TypedData) Digest() (ethgo.Hash, error) {
+	if t.Domain == nil {
+		return ethgo.Hash{}, fmt.Errorf("typed data does not have a domain")
+	}
+	separator, err := t.Domain.Separator()
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	hash, err := HashStruct(t.Types, t.PrimaryType, t.Message)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return ethgo.BytesToHash(keccak256([]byte{0x19, 0x01}, separator[:], hash[:])), nil
+}
+
+// Separator returns the domain separator, the hash of the domain struct
+func (d *TypedDataDomain) Separator() (ethgo.Hash, error) {
+	fields, values := d.fields()
+	types := TypedDataTypes{typedDataDomainName: fields}
+	return HashStruct(types, typedDataDomainName, values)
+}
+
+// fields returns the struct type of the domain and its values
+func (d *TypedDataDomain) fields() ([]TypedDataField, map[string]interface{}) {
+	fields := []TypedDataField{}
+	values := map[string]interface{}{}
+
+	if d.Name != "" {
+		fields = append(fields, TypedDataField{Name: "name", Type: "string"})
+		values["name"] = d.Name
+	}
+	if d.Version != "" {
+		fields = append(fields, TypedDataField{Name: "version", Type: "string"})
+		values["version"] = d.Version
+	}
+	if d.ChainID != nil {
+		fields = append(fields, TypedDataField{Name: "chainId", Type: "uint256"})
+		values["chainId"] = d.ChainID
+	}
+	if d.VerifyingContract != nil {
+		fields = append(fields, TypedDataField{Name: "verifyingContract", Type: "address"})
+		values["verifyingContract"] = *d.VerifyingContract
+	}
+	if d.Salt != nil {
+		fields = append(fields, TypedDataField{Name: "salt", Type: "bytes32"})
+		values["salt"] = *d.Salt
+	}
+	return fields, values
+}
+
+// EncodeType returns the encoding of the struct type with the name
+// and the types it references sorted by name
+// (i.e. 'Mail(Person from,Person to,string contents)Person(string name,address wallet)')
+func EncodeType(types TypedDataTypes, name string) (string, error) {
+	deps := map[string]struct{}{}
+	if err := typedDataDeps(types, name, deps); err != nil {
+		return "", err
+	}
+	delete(deps, name)
+
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+
+	var b strings.Builder
+	for _, typ := range append([]string{name}, sorted...) {
+		fields := make([]string, len(types[typ]))
+		for i, field := range types[typ] {
+			fields[i] = field.Type + " " + field.Name
+		}
+		b.WriteString(typ + "(" + strings.Join(fields, ",") + ")")
+	}
+	return b.String(), nil
+}
+
+func typedDataDeps(types TypedDataTypes, name string, deps map[string]struct{}) error {
+	if _, ok := deps[name]; ok {
+		return nil
+	}
+	fields, ok := types[name]
+	if !ok {
+		return fmt.Errorf("type '%s' not found", name)
+	}
+	deps[name] = struct{}{}
+
+	for _, field := range fields {
+		if base := typedDataBaseType(field.Type); isTypedDataStruct(types, base) {
+			if err := typedDataDeps(types, base, deps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// TypeHash returns the hash of the encoding of the struct type
+func TypeHash(types TypedDataTypes, name string) (ethgo.Hash, error) {
+	enc, err := EncodeType(types, name)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return ethgo.BytesToHash(keccak256([]byte(enc))), nil
+}
+
+// HashStruct returns the hash of a struct value:
+// keccak256(typeHash || encodeData(value))
+func HashStruct(types TypedDataTypes, name string, value map[string]interface{}) (ethgo.Hash, error) {
+	data, err := EncodeData(types, name, value)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return ethgo.BytesToHash(keccak256(data)), nil
+}
+
+// EncodeData returns the type hash of the struct followed by the encoding
+// of each one of its fields as a 32 bytes word
+func EncodeData(types TypedDataTypes, name string, value map[string]interface{}) ([]byte, error) {
+	typeHash, err := TypeHash(types, name)
+	if err != nil {
+		return nil, err
+	}
+
+	res := append([]byte{}, typeHash[:]...)
+	for _, field := range types[name] {
+		val, ok := value[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("field '%s' of type '%s' not found", field.Name, name)
+		}
+		word, err := encodeTypedDataValue(types, field.Type, val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode field '%s': %v", field.Name, err)
+		}
+		res = append(res, word...)
+	}
+	return res, nil
+}
+
+func encodeTypedDataValue(types TypedDataTypes, typ string, val interface{}) ([]byte, error) {
+	// arrays are encoded as the hash of the concatenated encoding of the elements
+	if indx := strings.LastIndex(typ, "["); indx != -1 && strings.HasSuffix(typ, "]") {
+		v := reflect.ValueOf(val)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, fmt.Errorf("expected an array for type %s", typ)
+		}
+		var data []byte
+		for i := 0; i < v.Len(); i++ {
+			word, err := encodeTypedDataValue(types, typ[:indx], v.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, word...)
+		}
+		return keccak256(data), nil
+	}
+
+	if isTypedDataStruct(types, typ) {
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a map for struct %s", typ)
+		}
+		hash, err := HashStruct(types, typ, obj)
+		if err != nil {
+			return nil, err
+		}
+		return hash[:], nil
+	}
+
+	t, err := NewType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t.Kind() {
+	case KindString:
+		str, ok := val.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a string but found %T", val)
+		}
+		return keccak256([]byte(str)), nil
+
+	case KindBytes:
+		buf, err := EncodePacked(val, t)
+		if err != nil {
+			return nil, err
+		}
+		return keccak256(buf), nil
+
+	default:
+		return Encode(val, t)
+	}
+}
+
+// typedDataBaseType returns the type without the array suffixes
+func typedDataBaseType(typ string) string {
+	if indx := strings.Index(typ, "["); indx != -1 {
+		return typ[:indx]
+	}
+	return typ
+}
+
+func isTypedDataStruct(types TypedDataTypes, typ string) bool {
+	_, ok := types[typ]
+	return ok
+}
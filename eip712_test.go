@@ -0,0 +1,98 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func mailTypedData() *TypedData {
+	contract := ethgo.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC")
+
+	return &TypedData{
+		Types: TypedDataTypes{
+			"Person": {
+				{Name: "name", Type: "string"},
+				{Name: "wallet", Type: "address"},
+			},
+			"Mail": {
+				{Name: "from", Type: "Person"},
+				{Name: "to", Type: "Person"},
+				{Name: "contents", Type: "string"},
+			},
+		},
+		PrimaryType: "Mail",
+		Domain: &TypedDataDomain{
+			Name:              "Ether Mail",
+			Version:           "1",
+			ChainID:           big.NewInt(1),
+			VerifyingContract: &contract,
+		},
+		Message: map[string]interface{}{
+			"from": map[string]interface{}{
+				"name":   "Cow",
+				"wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826",
+			},
+			"to": map[string]interface{}{
+				"name":   "Bob",
+				"wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB",
+			},
+			"contents": "Hello, Bob!",
+		},
+	}
+}
+
+func TestEIP712_Mail(t *testing.T) {
+	// example from the EIP-712 specification
+	typedData := mailTypedData()
+
+	enc, err := EncodeType(typedData.Types, "Mail")
+	require.NoError(t, err)
+	require.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", enc)
+
+	typeHash, err := TypeHash(typedData.Types, "Mail")
+	require.NoError(t, err)
+	require.Equal(t, "0xa0cedeb2dc280ba39b857546d74f5549c3a1d7bdc2dd96bf881f76108e23dac2", typeHash.String())
+
+	separator, err := typedData.Domain.Separator()
+	require.NoError(t, err)
+	require.Equal(t, "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", separator.String())
+
+	hash, err := HashStruct(typedData.Types, "Mail", typedData.Message)
+	require.NoError(t, err)
+	require.Equal(t, "0xc52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e", hash.String())
+
+	digest, err := typedData.Digest()
+	require.NoError(t, err)
+	require.Equal(t, "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", digest.String())
+}
+
+func TestEIP712_Arrays(t *testing.T) {
+	types := TypedDataTypes{
+		"Group": {
+			{Name: "members", Type: "address[]"},
+			{Name: "data", Type: "bytes"},
+		},
+	}
+	members := []interface{}{ethgo.Address{0x1}, ethgo.Address{0x2}}
+
+	data, err := EncodeData(types, "Group", map[string]interface{}{
+		"members": members,
+		"data":    "0x0102",
+	})
+	require.NoError(t, err)
+	require.Len(t, data, 3*32)
+
+	// arrays are the hash of the concatenated encoded elements
+	expected := keccak256(leftPad(ethgo.Address{0x1}.Bytes(), 32), leftPad(ethgo.Address{0x2}.Bytes(), 32))
+	require.Equal(t, expected, data[32:64])
+	require.Equal(t, keccak256([]byte{0x1, 0x2}), data[64:])
+
+	_, err = EncodeData(types, "Group", map[string]interface{}{"members": members})
+	require.EqualError(t, err, "field 'data' of type 'Group' not found")
+
+	_, err = EncodeType(types, "Unknown")
+	require.Error(t, err)
+}
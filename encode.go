@@ -16,47 +16,103 @@ var (
 	one  = big.NewInt(1)
 )
 
+// wordSize is the default size in bytes of a word in the standard encoding
+const wordSize = 32
+
 // Encode encodes a value
 func Encode(v interface{}, t *Type) ([]byte, error) {
-	return encode(reflect.ValueOf(v), t)
+	return encode(reflect.ValueOf(v), t, wordSize, &EncodeOptions{})
+}
+
+// EncodeAs encodes a value of type T with the standard encoding. The go
+// type T is checked against the type before encoding the value
+func EncodeAs[T any](v T, t *Type) ([]byte, error) {
+	if err := checkGoType(reflect.TypeOf((*T)(nil)).Elem(), t); err != nil {
+		return nil, err
+	}
+	return Encode(v, t)
+}
+
+// EncodeWithOptions encodes a value with the standard encoding using the
+// word size and the nil policy of the options
+func EncodeWithOptions(v interface{}, t *Type, opts *EncodeOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &EncodeOptions{}
+	}
+	w := wordSize
+	if opts.WordSize != 0 {
+		w = opts.WordSize
+	}
+	if w < 0 {
+		return nil, fmt.Errorf("invalid word size %d", w)
+	}
+	return encode(reflect.ValueOf(v), t, w, opts)
+}
+
+func encode(v reflect.Value, t *Type, w int, opts *EncodeOptions) ([]byte, error) {
+	res, err := encodeValue(v, t, w, opts)
+	if err != nil {
+		return nil, t.labelErr(err)
+	}
+	return res, nil
 }
 
-func encode(v reflect.Value, t *Type) ([]byte, error) {
+func encodeValue(v reflect.Value, t *Type, w int, opts *EncodeOptions) ([]byte, error) {
 	if v.Kind() == reflect.Interface {
 		v = v.Elem()
 	}
+	v, err := applyEncodeHook(v, t)
+	if err != nil {
+		return nil, err
+	}
+	if v, err = applyNilPolicy(v, t, opts); err != nil {
+		return nil, err
+	}
+	if err := checkWordSize(t, w); err != nil {
+		return nil, err
+	}
 
 	switch t.kind {
 	case KindSlice, KindArray:
-		return encodeSliceAndArray(v, t)
+		return encodeSliceAndArray(v, t, w, opts)
 
 	case KindTuple:
-		return encodeTuple(v, t)
+		return encodeTuple(v, t, w, opts)
 
 	case KindString:
-		return encodeString(v)
+		return encodeString(v, w)
 
 	case KindBool:
-		return encodeBool(v)
+		return encodeBool(v, w)
 
 	case KindAddress:
-		return encodeAddress(v)
+		return encodeAddress(v, w)
 
 	case KindInt, KindUInt:
-		return encodeNum(v)
+		if v, err = encodeEnumName(v, t); err != nil {
+			return nil, err
+		}
+		return encodeNum(v, w)
 
 	case KindBytes:
-		return encodeBytes(v)
+		return encodeBytes(v, w)
 
 	case KindFixedBytes, KindFunction:
-		return encodeFixedBytes(v)
+		return encodeFixedBytes(v, w)
+
+	case KindFixedPoint:
+		n, err := encodeFixed(v, t)
+		if err != nil {
+			return nil, err
+		}
+		return toWord(n, w), nil
 
 	default:
 		return nil, fmt.Errorf("encoding not available for type '%s'", t.kind)
 	}
 }
 
-func encodeSliceAndArray(v reflect.Value, t *Type) ([]byte, error) {
+func encodeSliceAndArray(v reflect.Value, t *Type, w int, opts *EncodeOptions) ([]byte, error) {
 	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
 		return nil, encodeErr(v, t.kind.String())
 	}
@@ -73,24 +129,27 @@ func encodeSliceAndArray(v reflect.Value, t *Type) ([]byte, error) {
 
 	var ret, tail []byte
 	if t.isVariableInput() {
-		ret = append(ret, packNum(v.Len())...)
+		ret = append(ret, packNum(v.Len(), w)...)
 	}
 
 	offset := 0
 	isDynamic := t.elem.isDynamicType()
 	if isDynamic {
-		offset = getTypeSize(t.elem) * v.Len()
+		offset = getTypeSize(t.elem, w) * v.Len()
 	}
 
 	for i := 0; i < v.Len(); i++ {
-		val, err := encode(v.Index(i), t.elem)
+		if isNilElem(v.Index(i)) && opts.NilPolicy != NilPolicyZero {
+			return nil, fmt.Errorf("nil value at index %d", i)
+		}
+		val, err := encode(v.Index(i), t.elem, w, opts)
 		if err != nil {
 			return nil, err
 		}
 		if !isDynamic {
 			ret = append(ret, val...)
 		} else {
-			ret = append(ret, packNum(offset)...)
+			ret = append(ret, packNum(offset, w)...)
 			offset += len(val)
 			tail = append(tail, val...)
 		}
@@ -98,7 +157,7 @@ func encodeSliceAndArray(v reflect.Value, t *Type) ([]byte, error) {
 	return append(ret, tail...), nil
 }
 
-func encodeTuple(v reflect.Value, t *Type) ([]byte, error) {
+func encodeTuple(v reflect.Value, t *Type, w int, opts *EncodeOptions) ([]byte, error) {
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
@@ -128,7 +187,7 @@ func encodeTuple(v reflect.Value, t *Type) ([]byte, error) {
 
 	offset := 0
 	for _, elem := range t.tuple {
-		offset += getTypeSize(elem.Elem)
+		offset += getTypeSize(elem.Elem, w)
 	}
 
 	var ret, tail []byte
@@ -148,12 +207,15 @@ func encodeTuple(v reflect.Value, t *Type) ([]byte, error) {
 			return nil, fmt.Errorf("cannot get key %s", elem.Name)
 		}
 
-		val, err := encode(aux, elem.Elem)
+		if isNilElem(aux) && opts.NilPolicy != NilPolicyZero {
+			return nil, fmt.Errorf("nil value for tuple element '%s' of type %s", tupleElemName(elem, i), elem.Elem.String())
+		}
+		val, err := encode(aux, elem.Elem, w, opts)
 		if err != nil {
 			return nil, err
 		}
 		if elem.Elem.isDynamicType() {
-			ret = append(ret, packNum(offset)...)
+			ret = append(ret, packNum(offset, w)...)
 			tail = append(tail, val...)
 			offset += len(val)
 		} else {
@@ -164,13 +226,68 @@ func encodeTuple(v reflect.Value, t *Type) ([]byte, error) {
 	return append(ret, tail...), nil
 }
 
+// applyNilPolicy replaces the nil values with the zero value of the type
+// or returns an error depending on the nil policy
+func applyNilPolicy(v reflect.Value, t *Type, opts *EncodeOptions) (reflect.Value, error) {
+	if !isNil(v) {
+		return v, nil
+	}
+	if opts.NilPolicy == NilPolicyZero {
+		return reflect.ValueOf(zeroValue(t)), nil
+	}
+	return v, fmt.Errorf("cannot encode nil value as %s", t.String())
+}
+
+// isNilElem returns whether the element of a list or a tuple is nil,
+// including nil pointers stored in interfaces
+func isNilElem(v reflect.Value) bool {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+	return isNil(v)
+}
+
+// zeroValue returns a value that encodes as the zero value of the type
+func zeroValue(t *Type) interface{} {
+	switch t.Kind() {
+	case KindTuple:
+		res := make(map[string]interface{}, len(t.TupleElems()))
+		for indx, elem := range t.TupleElems() {
+			res[tupleElemName(elem, indx)] = zeroValue(elem.Elem)
+		}
+		return res
+
+	case KindArray:
+		res := reflect.New(reflect.ArrayOf(t.Size(), interfaceT)).Elem()
+		for i := 0; i < t.Size(); i++ {
+			res.Index(i).Set(reflect.ValueOf(zeroValue(t.Elem())))
+		}
+		return res.Interface()
+
+	case KindSlice:
+		return []interface{}{}
+
+	case KindInt, KindUInt:
+		return new(big.Int)
+
+	case KindFixedPoint:
+		return new(big.Rat)
+
+	case KindBytes:
+		return []byte{}
+
+	default:
+		return reflect.Zero(t.GoType()).Interface()
+	}
+}
+
 func convertArrayToBytes(value reflect.Value) reflect.Value {
 	slice := reflect.MakeSlice(reflect.TypeOf([]byte{}), value.Len(), value.Len())
 	reflect.Copy(slice, value)
 	return slice
 }
 
-func encodeFixedBytes(v reflect.Value) ([]byte, error) {
+func encodeFixedBytes(v reflect.Value, w int) ([]byte, error) {
 	if v.Kind() == reflect.Array {
 		v = convertArrayToBytes(v)
 	}
@@ -182,10 +299,10 @@ func encodeFixedBytes(v reflect.Value) ([]byte, error) {
 
 		v = reflect.ValueOf(value)
 	}
-	return rightPad(v.Bytes(), 32), nil
+	return rightPad(v.Bytes(), w), nil
 }
 
-func encodeAddress(v reflect.Value) ([]byte, error) {
+func encodeAddress(v reflect.Value, w int) ([]byte, error) {
 	if v.Kind() == reflect.Array {
 		v = convertArrayToBytes(v)
 	}
@@ -196,10 +313,10 @@ func encodeAddress(v reflect.Value) ([]byte, error) {
 		}
 		v = reflect.ValueOf(addr.Bytes())
 	}
-	return leftPad(v.Bytes(), 32), nil
+	return leftPad(v.Bytes(), w), nil
 }
 
-func encodeBytes(v reflect.Value) ([]byte, error) {
+func encodeBytes(v reflect.Value, w int) ([]byte, error) {
 	if v.Kind() == reflect.Array {
 		v = convertArrayToBytes(v)
 	}
@@ -211,69 +328,96 @@ func encodeBytes(v reflect.Value) ([]byte, error) {
 
 		v = reflect.ValueOf(value)
 	}
-	return packBytesSlice(v.Bytes(), v.Len())
+	return packBytesSlice(v.Bytes(), v.Len(), w)
 }
 
-func encodeString(v reflect.Value) ([]byte, error) {
+func encodeString(v reflect.Value, w int) ([]byte, error) {
 	if v.Kind() != reflect.String {
 		return nil, encodeErr(v, "string")
 	}
-	return packBytesSlice([]byte(v.String()), v.Len())
+	return packBytesSlice([]byte(v.String()), v.Len(), w)
 }
 
-func packBytesSlice(buf []byte, l int) ([]byte, error) {
-	len, err := encodeNum(reflect.ValueOf(l))
+func packBytesSlice(buf []byte, l int, w int) ([]byte, error) {
+	len, err := encodeNum(reflect.ValueOf(l), w)
 	if err != nil {
 		return nil, err
 	}
-	return append(len, rightPad(buf, (l+31)/32*32)...), nil
+	return append(len, rightPad(buf, (l+w-1)/w*w)...), nil
 }
 
-func packNum(offset int) []byte {
-	n, _ := encodeNum(reflect.ValueOf(offset))
+func packNum(offset int, w int) []byte {
+	n, _ := encodeNum(reflect.ValueOf(offset), w)
 	return n
 }
 
-func encodeNum(v reflect.Value) ([]byte, error) {
+func encodeNum(v reflect.Value, w int) ([]byte, error) {
+	if n, ok := uint256ToBig(v); ok {
+		return toWord(n, w), nil
+	}
 	switch v.Kind() {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return toU256(new(big.Int).SetUint64(v.Uint())), nil
+		return toWord(new(big.Int).SetUint64(v.Uint()), w), nil
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return toU256(big.NewInt(v.Int())), nil
+		return toWord(big.NewInt(v.Int()), w), nil
 
 	case reflect.Ptr:
 		if v.Type() != bigIntT {
 			return nil, encodeErr(v.Elem(), "number")
 		}
-		return toU256(v.Interface().(*big.Int)), nil
+		return toWord(v.Interface().(*big.Int), w), nil
 
 	case reflect.Float64:
-		return encodeNum(reflect.ValueOf(int64(v.Float())))
+		return encodeNum(reflect.ValueOf(int64(v.Float())), w)
 
 	case reflect.String:
-		n, ok := new(big.Int).SetString(v.String(), 10)
-		if !ok {
-			n, ok = new(big.Int).SetString(v.String()[2:], 16)
-			if !ok {
-				return nil, encodeErr(v, "number")
-			}
+		n, err := parseNumString(v.String())
+		if err != nil {
+			return nil, err
 		}
-		return encodeNum(reflect.ValueOf(n))
+		return encodeNum(reflect.ValueOf(n), w)
 
 	default:
 		return nil, encodeErr(v, "number")
 	}
 }
 
-func encodeBool(v reflect.Value) ([]byte, error) {
+// parseNumString parses a decimal or a 0x prefixed hex number with
+// an optional sign
+func parseNumString(str string) (*big.Int, error) {
+	num := str
+	neg := false
+	if strings.HasPrefix(num, "-") || strings.HasPrefix(num, "+") {
+		neg = num[0] == '-'
+		num = num[1:]
+	}
+	base := 10
+	if strings.HasPrefix(num, "0x") || strings.HasPrefix(num, "0X") {
+		base = 16
+		num = num[2:]
+	}
+	if strings.HasPrefix(num, "-") || strings.HasPrefix(num, "+") {
+		return nil, fmt.Errorf("failed to parse '%s' as number", str)
+	}
+	n, ok := new(big.Int).SetString(num, base)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse '%s' as number", str)
+	}
+	if neg {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+func encodeBool(v reflect.Value, w int) ([]byte, error) {
 	if v.Kind() != reflect.Bool {
 		return nil, encodeErr(v, "bool")
 	}
 	if v.Bool() {
-		return leftPad(one.Bytes(), 32), nil
+		return leftPad(one.Bytes(), w), nil
 	}
-	return leftPad(zero.Bytes(), 32), nil
+	return leftPad(zero.Bytes(), w), nil
 }
 
 func encodeErr(v reflect.Value, t string) error {
@@ -295,8 +439,8 @@ func mapFromStruct(v reflect.Value) (reflect.Value, error) {
 		}
 
 		name := strings.ToLower(f.Name)
-		if tagValue != "" {
-			name = tagValue
+		if tagName, _ := parseAbiTag(tagValue); tagName != "" {
+			name = tagName
 		}
 		if _, ok := res[name]; !ok {
 			res[name] = v.Field(i).Interface()
@@ -310,16 +454,43 @@ var (
 	tt256m1 = new(big.Int).Sub(tt256, big.NewInt(1)) // 2 ** 256 - 1
 )
 
-// U256 converts a big Int into a 256bit EVM number.
-func toU256(n *big.Int) []byte {
+// toWord converts a big Int into a number of w bytes in two's complement
+// (a 256bit EVM number for the default word size)
+func toWord(n *big.Int, w int) []byte {
 	b := new(big.Int)
 	b = b.Set(n)
 
-	if b.Sign() < 0 || b.BitLen() > 256 {
-		b.And(b, tt256m1)
+	mask := tt256m1
+	if w != wordSize {
+		mask = new(big.Int).Sub(new(big.Int).Lsh(one, uint(w*8)), one)
 	}
+	if b.Sign() < 0 || b.BitLen() > w*8 {
+		b.And(b, mask)
+	}
+
+	return leftPad(b.Bytes(), w)
+}
 
-	return leftPad(b.Bytes(), 32)
+// checkWordSize checks that the static value of a type fits in a word
+// of w bytes
+func checkWordSize(t *Type, w int) error {
+	var size int
+	switch t.kind {
+	case KindInt, KindUInt, KindFixedPoint:
+		size = t.size / 8
+	case KindFixedBytes:
+		size = t.size
+	case KindAddress:
+		size = 20
+	case KindFunction:
+		size = 24
+	default:
+		size = 1
+	}
+	if size > w {
+		return fmt.Errorf("type %s does not fit in a word of %d bytes", t.String(), w)
+	}
+	return nil
 }
 
 func padBytes(b []byte, size int, left bool) []byte {
@@ -2,30 +2,695 @@
 package abi
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
+	"io"
 	"math/big"
 	"reflect"
 	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/holiman/uint256"
 	"github.com/umbracle/ethgo"
 )
 
-// Encode encodes a value
+// EncodeOptions are the options to customize the encoding. The packed
+// encoding uses all of them except WordSize, while the standard encoding
+// (EncodeWithOptions) only uses WordSize and NilPolicy
+type EncodeOptions struct {
+	// ParseStringers encodes values implementing fmt.Stringer into
+	// numeric types by parsing the output of their String method
+	ParseStringers bool
+
+	// Base64Bytes decodes strings with the 'base64:' prefix as
+	// base64 when encoding bytes and fixed bytes
+	Base64Bytes bool
+
+	// TextBytes encodes strings into fixed bytes as their utf-8
+	// bytes (i.e. 'DAI' into bytes32) instead of decoding them as hex
+	TextBytes bool
+
+	// NumericBytes encodes *big.Int values into fixed bytes as left
+	// padded big endian numbers (i.e. 255 into bytes32)
+	NumericBytes bool
+
+	// ValidateChecksum validates the EIP-55 checksum of the mixed case
+	// string addresses and returns an error if it does not match instead
+	// of encoding the address. All lower or upper case strings have no
+	// checksum and are always accepted
+	ValidateChecksum bool
+
+	// NilPolicy is the behavior with nil pointers and interfaces, which
+	// return an error by default
+	NilPolicy NilPolicy
+
+	// CheckRange returns an error when an integer value does not fit in
+	// the type (i.e. 300 as uint8 or -1 as uint256). By default the native
+	// and big integers are truncated to the size of the type like a cast
+	// in solidity (300 as uint8 is 0x2c), while numeric strings are always
+	// checked since they are parsed and not cast
+	CheckRange bool
+
+	// TightArrays encodes the array elements without padding. By default
+	// the elementary values of arrays are padded to 32 bytes like in the
+	// abi.encodePacked function of solidity
+	TightArrays bool
+
+	// WordSize is the size in bytes of the words in the standard
+	// encoding (EncodeWithOptions), 32 by default. Packed mode ignores it
+	WordSize int
+}
+
+// NilPolicy is the behavior of the encoding with nil values
+type NilPolicy int
+
+const (
+	// NilPolicyError returns an error with the path of the nil value
+	NilPolicyError NilPolicy = iota
+
+	// NilPolicyZero encodes the nil values as the zero value of their type
+	NilPolicyZero
+)
+
+// PreEncoded is a value already encoded in packed mode. It is appended
+// verbatim to the output without checking it against the type
+type PreEncoded []byte
+
+var preEncodedT = reflect.TypeOf(PreEncoded{})
+
+// AddressProvider is implemented by the values that resolve to an
+// address (i.e. a resolved ENS name) and are encoded as that address
+type AddressProvider interface {
+	Address() ethgo.Address
+}
+
+var addressProviderT = reflect.TypeOf((*AddressProvider)(nil)).Elem()
+
+// EncodePacked encodes a value with the non-standard packed mode. Tuples
+// can be encoded from structs, maps indexed by the element names or any
+// slice or array with at least as many values as the tuple elements, in
+// which case the first values are used in order and the rest are ignored.
+// Bytes values can also be given as a func() ([]byte, error) that is only
+// invoked at encode time
 func EncodePacked(v interface{}, t *Type) ([]byte, error) {
-	return encodePacked(reflect.ValueOf(v), t)
+	return EncodePackedWithOptions(v, t, nil)
 }
 
-func encodePacked(v reflect.Value, t *Type) ([]byte, error) {
+// EncodePackedTyped encodes a value with the type given as a string. The
+// parsed types are cached so the string is only parsed once
+func EncodePackedTyped(typeStr string, v interface{}) ([]byte, error) {
+	t, err := cachedType(typeStr)
+	if err != nil {
+		return nil, err
+	}
+	return EncodePacked(v, t)
+}
+
+// EncodePackedAs encodes a value of type T in packed mode. The go type T
+// is checked against the type before encoding the value
+func EncodePackedAs[T any](v T, t *Type) ([]byte, error) {
+	if err := checkGoType(reflect.TypeOf((*T)(nil)).Elem(), t); err != nil {
+		return nil, err
+	}
+	return EncodePacked(v, t)
+}
+
+// EncodePackedSlice encodes the values as the packed elements of an array
+// of type elem, padded to 32 bytes. The go type T is checked against the
+// element type once before encoding the values
+func EncodePackedSlice[T any](values []T, elem *Type) ([]byte, error) {
+	return EncodePackedSliceWithOptions(values, elem, nil)
+}
+
+// EncodePackedSliceWithOptions encodes the values as the packed elements
+// of an array of type elem with the given options (i.e. TightArrays)
+func EncodePackedSliceWithOptions[T any](values []T, elem *Type, opts *EncodeOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &EncodeOptions{}
+	}
+	rv := reflect.ValueOf(values)
+	if err := checkGoType(rv.Type().Elem(), elem); err != nil {
+		return nil, err
+	}
+	if elem.Kind() == KindUInt && elem.Size() >= 64 && rv.Type().Elem().Kind() == reflect.Uint64 {
+		if _, hooked := getTypeHook(rv.Type().Elem()); !hooked {
+			size, _ := packedElemSize(elem, opts.TightArrays)
+			return encodeUint64sPacked(rv, size), nil
+		}
+	}
+
+	w := &appendWriter{}
+	if size, ok := packedElemSize(elem, opts.TightArrays); ok {
+		w.buf = make([]byte, 0, size*len(values))
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if err := encodePackedTo(w, rv.Index(i), elem, opts, true); err != nil {
+			return nil, fmt.Errorf("failed to encode value at index %d: %v", i, err)
+		}
+	}
+	return w.buf, nil
+}
+
+// EncodePackedWithOptions encodes a value with the given options
+func EncodePackedWithOptions(v interface{}, t *Type, opts *EncodeOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &EncodeOptions{}
+	}
+	if canEncodeFast(v, opts) {
+		if res, ok, err := encodePackedFast(v, t); ok {
+			return res, err
+		}
+	}
+	return encodePacked(reflect.ValueOf(v), t, opts)
+}
+
+// maxPooledBuffer is the largest capacity of a buffer returned to the pool,
+// bigger buffers are released to avoid pinning their memory
+const maxPooledBuffer = 64 * 1024
+
+// bufferPool holds the buffers used to encode tuples and arrays
+var bufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// encodePackedPooled writes the elements of a tuple or an array into a
+// pooled buffer and copies the result out once, instead of appending the
+// encoding of every nested value into a new slice
+func encodePackedPooled(v reflect.Value, t *Type, opts *EncodeOptions) ([]byte, error) {
+	return encodePooled(0, func(buf *bytes.Buffer) error {
+		return encodePackedTo(buf, v, t, opts, false)
+	})
+}
+
+// encodePooled calls fn with a buffer from the pool, grown to size bytes
+// if it is known, and returns a copy of the bytes written to it
+func encodePooled(size int, fn func(buf *bytes.Buffer) error) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	buf.Grow(size)
+	defer func() {
+		if buf.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buf)
+		}
+	}()
+
+	if err := fn(buf); err != nil {
+		return nil, err
+	}
+	res := make([]byte, buf.Len())
+	copy(res, buf.Bytes())
+	return res, nil
+}
+
+// canEncodeFast returns whether the value can skip the reflection path
+// of the packed encoding, that is, there is no type hook for its go type
+// and no option changes the encoding of the values of encodePackedFast
+func canEncodeFast(v interface{}, opts *EncodeOptions) bool {
+	if opts.CheckRange || opts.ParseStringers || opts.Base64Bytes || opts.TextBytes || opts.NumericBytes || opts.ValidateChecksum {
+		return false
+	}
+	if v == nil {
+		return true
+	}
+	_, hooked := getTypeHook(reflect.TypeOf(v))
+	return !hooked
+}
+
+// encodePackedFast encodes the most common scalar values without
+// using reflection. It returns false if the value is not supported
+func encodePackedFast(v interface{}, t *Type) ([]byte, bool, error) {
+	if t.enum != nil {
+		// the values of the enums are checked in the slow path
+		return nil, false, nil
+	}
+	switch obj := v.(type) {
+	case uint64:
+		if t.Kind() == KindUInt || t.Kind() == KindInt {
+			return putUint64(obj, t.Size()/8), true, nil
+		}
+
+	case *big.Int:
+		if obj != nil && (t.Kind() == KindUInt || t.Kind() == KindInt) {
+			return toUSize(obj, t.Size()), true, nil
+		}
+
+	case *uint256.Int:
+		if obj != nil && (t.Kind() == KindUInt || t.Kind() == KindInt) {
+			return putUint256(obj, t.Size()), true, nil
+		}
+
+	case ethgo.Address:
+		if t.Kind() == KindAddress {
+			return append([]byte{}, obj[:]...), true, nil
+		}
+
+	case [32]byte:
+		if t.Kind() == KindFixedBytes && t.Size() == 32 {
+			return append([]byte{}, obj[:]...), true, nil
+		}
+
+	case ethgo.Hash:
+		if t.Kind() == KindFixedBytes && t.Size() == 32 {
+			return append([]byte{}, obj[:]...), true, nil
+		}
+
+	case bool:
+		if t.Kind() == KindBool {
+			if obj {
+				return []byte{1}, true, nil
+			}
+			return []byte{0}, true, nil
+		}
+
+	case []byte:
+		if t.Kind() == KindBytes {
+			return append([]byte{}, obj...), true, nil
+		}
+
+	case string:
+		if t.Kind() == KindString {
+			return []byte(obj), true, nil
+		}
+
+	default:
+		if res, ok := encodeGethFast(v, t); ok {
+			return res, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// AppendPacked appends the packed encoding of the value to dst and returns
+// the extended buffer. The common scalar values are written directly into
+// dst, so there are no allocations if dst has enough capacity
+func AppendPacked(dst []byte, v interface{}, t *Type) ([]byte, error) {
+	opts := &EncodeOptions{}
+	if canEncodeFast(v, opts) {
+		if res, ok := appendPackedFast(dst, v, t); ok {
+			return res, nil
+		}
+	}
+	w := &appendWriter{buf: dst}
+	if err := encodePackedTo(w, reflect.ValueOf(v), t, opts, false); err != nil {
+		return dst, err
+	}
+	return w.buf, nil
+}
+
+// appendWriter is a writer that appends the written bytes to a slice
+type appendWriter struct {
+	buf []byte
+}
+
+func (a *appendWriter) Write(p []byte) (int, error) {
+	a.buf = append(a.buf, p...)
+	return len(p), nil
+}
+
+// appendPackedFast appends the scalar values supported by encodePackedFast
+// without intermediate buffers. It returns false if the value is not supported
+func appendPackedFast(dst []byte, v interface{}, t *Type) ([]byte, bool) {
+	if t.enum != nil {
+		return dst, false
+	}
+	switch obj := v.(type) {
+	case uint64:
+		if t.Kind() == KindUInt || t.Kind() == KindInt {
+			dst, word := growBytes(dst, t.Size()/8)
+			putUint64Into(word, obj)
+			return dst, true
+		}
+
+	case *big.Int:
+		// negative and overflowing numbers are truncated in the slow path
+		if obj != nil && obj.Sign() >= 0 && obj.BitLen() <= t.Size() && (t.Kind() == KindUInt || t.Kind() == KindInt) {
+			dst, word := growBytes(dst, t.Size()/8)
+			obj.FillBytes(word)
+			return dst, true
+		}
+
+	case *uint256.Int:
+		if obj != nil && (t.Kind() == KindUInt || t.Kind() == KindInt) {
+			buf := obj.Bytes32()
+			return append(dst, buf[32-t.Size()/8:]...), true
+		}
+
+	case ethgo.Address:
+		if t.Kind() == KindAddress {
+			return append(dst, obj[:]...), true
+		}
+
+	case [32]byte:
+		if t.Kind() == KindFixedBytes && t.Size() == 32 {
+			return append(dst, obj[:]...), true
+		}
+
+	case ethgo.Hash:
+		if t.Kind() == KindFixedBytes && t.Size() == 32 {
+			return append(dst, obj[:]...), true
+		}
+
+	case bool:
+		if t.Kind() == KindBool {
+			if obj {
+				return append(dst, 1), true
+			}
+			return append(dst, 0), true
+		}
+
+	case []byte:
+		if t.Kind() == KindBytes {
+			return append(dst, obj...), true
+		}
+
+	case string:
+		if t.Kind() == KindString {
+			return append(dst, obj...), true
+		}
+	}
+	return dst, false
+}
+
+// growBytes extends the buffer with n zero bytes and returns the extended
+// buffer and the new bytes
+func growBytes(dst []byte, n int) ([]byte, []byte) {
+	l := len(dst)
+	if cap(dst)-l < n {
+		buf := make([]byte, l, 2*cap(dst)+n)
+		copy(buf, dst)
+		dst = buf
+	}
+	dst = dst[:l+n]
+	word := dst[l:]
+	for i := range word {
+		word[i] = 0
+	}
+	return dst, word
+}
+
+// putUint64Into writes the number as a big endian integer that fills
+// the buffer, truncating the higher bytes if it does not fit
+func putUint64Into(buf []byte, n uint64) {
+	for i := len(buf) - 1; i >= 0 && n != 0; i-- {
+		buf[i] = byte(n)
+		n >>= 8
+	}
+}
+
+// putUint64 writes the number as a big endian integer of the given
+// size in bytes, truncating the higher bytes if it does not fit
+func putUint64(n uint64, size int) []byte {
+	var tmp [8]byte
+	binary.BigEndian.PutUint64(tmp[:], n)
+
+	buf := make([]byte, size)
+	if size >= 8 {
+		copy(buf[size-8:], tmp[:])
+	} else {
+		copy(buf, tmp[8-size:])
+	}
+	return buf
+}
+
+// EncodePackedArgs encodes a list of values with their types and
+// concatenates the results
+func EncodePackedArgs(types []*Type, values []interface{}) ([]byte, error) {
+	return encodePackedList(types, values, &EncodeOptions{})
+}
+
+// EncodePackedToHex encodes a value in packed mode and returns the
+// result as a 0x prefixed hex string
+func EncodePackedToHex(v interface{}, t *Type) (string, error) {
+	res, err := EncodePacked(v, t)
+	if err != nil {
+		return "", err
+	}
+	return encodeHex(res), nil
+}
+
+// EncodePackedValues encodes several values with their types one after
+// the other, the equivalent of abi.encodePacked(a, b, c) in solidity
+func EncodePackedValues(types []*Type, values ...interface{}) ([]byte, error) {
+	return EncodePackedArgs(types, values)
+}
+
+// PackedSize returns the length of the value encoded in packed mode.
+// Lazy bytes values are not invoked and return ErrLazySize
+func PackedSize(v interface{}, t *Type) (int, error) {
+	return packedSize(reflect.ValueOf(v), t, &EncodeOptions{})
+}
+
+// PackedSizeArgs returns the length of the list of values encoded
+// in packed mode. Lazy bytes values are not invoked and return ErrLazySize
+func PackedSizeArgs(types []*Type, values []interface{}) (int, error) {
+	return packedSizeList(types, values, &EncodeOptions{})
+}
+
+// encodePackedList encodes each value with its type and concatenates the
+// results in a pooled buffer, sized up front unless there are lazy values
+func encodePackedList(types []*Type, values []interface{}, opts *EncodeOptions) ([]byte, error) {
+	if len(types) != len(values) {
+		return nil, fmt.Errorf("expected %d values but found %d", len(types), len(values))
+	}
+	// the values that cannot be sized (i.e. lazy bytes) are left to the
+	// encoder, which reports the invalid ones with their location
+	size, err := packedSizeList(types, values, opts)
+	if err != nil {
+		size = 0
+	}
+	return encodePooled(size, func(buf *bytes.Buffer) error {
+		for i, t := range types {
+			if err := encodePackedTo(buf, reflect.ValueOf(values[i]), t, opts, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func packedSizeList(types []*Type, values []interface{}, opts *EncodeOptions) (int, error) {
+	if len(types) != len(values) {
+		return 0, fmt.Errorf("expected %d values but found %d", len(types), len(values))
+	}
+	total := 0
+	for i, t := range types {
+		size, err := packedSize(reflect.ValueOf(values[i]), t, opts)
+		if err != nil {
+			return 0, err
+		}
+		total += size
+	}
+	return total, nil
+}
+
+// packedSize computes the length of the encoded value without encoding it
+func packedSize(v reflect.Value, t *Type, opts *EncodeOptions) (int, error) {
 	if v.Kind() == reflect.Interface {
 		v = v.Elem()
 	}
+	v, err := applyEncodeHook(v, t)
+	if err != nil {
+		return 0, err
+	}
+	if v, err = applyNilPolicy(v, t, opts); err != nil {
+		return 0, err
+	}
+	if v.Type() == preEncodedT {
+		return v.Len(), nil
+	}
+	if size, ok := packedByteSize(t, opts.TightArrays); ok {
+		return size, nil
+	}
 
 	switch t.Kind() {
+	case KindString:
+		if v.Kind() != reflect.String {
+			return 0, encodeErr(v, "string")
+		}
+		return v.Len(), nil
+
+	case KindBytes:
+		switch v.Kind() {
+		case reflect.Func:
+			return 0, ErrLazySize
+		case reflect.String:
+			buf, err := bytesFromString(v.String(), opts)
+			if err != nil {
+				return 0, err
+			}
+			return len(buf), nil
+		case reflect.Slice, reflect.Array:
+			return v.Len(), nil
+		}
+		return 0, encodeErr(v, "bytes")
+
 	case KindSlice, KindArray:
-		return encodeSliceAndArrayPacked(v, t)
+		if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+			return 0, encodeErr(v, t.Kind().String())
+		}
+		if size, ok := packedElemSize(t.Elem(), opts.TightArrays); ok {
+			return size * v.Len(), nil
+		}
+		total := 0
+		for i := 0; i < v.Len(); i++ {
+			size, err := packedSize(v.Index(i), t.Elem(), opts)
+			if err != nil {
+				return 0, err
+			}
+			total += size
+		}
+		return total, nil
+
+	case KindTuple:
+		values, err := tupleValues(v, t)
+		if err != nil {
+			return 0, err
+		}
+		total := 0
+		for i, elem := range t.TupleElems() {
+			size, err := packedSize(values[i], elem.Elem, opts)
+			if err != nil {
+				return 0, err
+			}
+			total += size
+		}
+		return total, nil
+
+	default:
+		return 0, fmt.Errorf("encoding not available for type '%s'", t.Kind())
+	}
+}
 
+// encodePacked returns the packed encoding of the value. Tuples and
+// arrays are written by encodePackedTo into a pooled buffer
+func encodePacked(v reflect.Value, t *Type, opts *EncodeOptions) ([]byte, error) {
+	switch t.Kind() {
+	case KindTuple, KindSlice, KindArray:
+		return encodePackedPooled(v, t, opts)
+	}
+	v, err := prepareEncodePacked(v, t, opts)
+	if err != nil {
+		return nil, newABIError("encode", t, err)
+	}
+	if v.Type() == preEncodedT {
+		return v.Bytes(), nil
+	}
+	return encodePackedElem(v, t, opts, false)
+}
+
+// encodePackedTo writes the packed encoding of the value of type t to w.
+// Tuples and arrays are written one element at a time and the elementary
+// values inside arrays are padded unless the arrays are tight
+func encodePackedTo(w io.Writer, v reflect.Value, t *Type, opts *EncodeOptions, arrayElem bool) error {
+	v, err := prepareEncodePacked(v, t, opts)
+	if err != nil {
+		return newABIError("encode", t, err)
+	}
+	if v.Type() == preEncodedT {
+		_, err := w.Write(v.Bytes())
+		return err
+	}
+
+	switch t.Kind() {
 	case KindTuple:
-		return encodeTuplePacked(v, t)
+		values, err := tupleValues(v, t)
+		if err != nil {
+			return newABIError("encode", t, err)
+		}
+		for indx, elem := range t.TupleElems() {
+			if err := encodePackedTo(w, values[indx], elem.Elem, opts, false); err != nil {
+				return withPath(err, tupleElemName(elem, indx))
+			}
+		}
+		return nil
+
+	case KindSlice, KindArray:
+		if err := checkPackedList(v, t); err != nil {
+			return newABIError("encode", t, err)
+		}
+		if elem := t.Elem(); elem.Kind() == KindUInt && elem.Size() >= 64 && v.Type().Elem().Kind() == reflect.Uint64 {
+			if _, hooked := getTypeHook(v.Type().Elem()); !hooked {
+				size, _ := packedElemSize(elem, opts.TightArrays)
+				_, err := w.Write(encodeUint64sPacked(v, size))
+				return err
+			}
+		}
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if isNil(elem) && opts.NilPolicy != NilPolicyZero {
+				return newABIError("encode", t, fmt.Errorf("nil value at index %d", i))
+			}
+			if err := encodePackedTo(w, elem, t.Elem(), opts, true); err != nil {
+				return withPath(err, fmt.Sprintf("[%d]", i))
+			}
+		}
+		return nil
+
+	case KindBytes:
+		// large values are written without copying them
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			_, err := w.Write(v.Bytes())
+			return err
+		}
+
+	case KindString:
+		if v.Kind() == reflect.String {
+			_, err := io.WriteString(w, v.String())
+			return err
+		}
+	}
+
+	buf, err := encodePackedElem(v, t, opts, arrayElem)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(buf)
+	return err
+}
+
+// prepareEncodePacked returns the value to encode with the type hooks
+// and the nil policy applied
+func prepareEncodePacked(v reflect.Value, t *Type, opts *EncodeOptions) (reflect.Value, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	v, err := applyEncodeHook(v, t)
+	if err != nil {
+		return v, err
+	}
+	return applyNilPolicy(v, t, opts)
+}
 
+// encodePackedElem encodes an elementary value. The elements of arrays
+// are padded to 32 bytes unless the arrays are tight
+func encodePackedElem(v reflect.Value, t *Type, opts *EncodeOptions, arrayElem bool) ([]byte, error) {
+	if !arrayElem && v.CanInterface() && canEncodeFast(v.Interface(), opts) {
+		if res, ok, err := encodePackedFast(v.Interface(), t); ok {
+			if err != nil {
+				return nil, newABIError("encode", t, err)
+			}
+			return res, nil
+		}
+	}
+	val, err := encodePackedValue(v, t, opts)
+	if err != nil {
+		return nil, newABIError("encode", t, err)
+	}
+	if !arrayElem || opts.TightArrays {
+		return val, nil
+	}
+	return padArrayElemPacked(val, t), nil
+}
+
+func encodePackedValue(v reflect.Value, t *Type, opts *EncodeOptions) ([]byte, error) {
+	var err error
+
+	switch t.Kind() {
 	case KindString:
 		return encodeStringPacked(v)
 
@@ -33,50 +698,96 @@ func encodePacked(v reflect.Value, t *Type) ([]byte, error) {
 		return encodeBoolPacked(v)
 
 	case KindAddress:
-		return encodeAddressPacked(v)
+		return encodeAddressPacked(v, opts)
 
 	case KindInt, KindUInt:
-		return encodeNumPacked(v,t)
+		if v, err = encodeEnumName(v, t); err != nil {
+			return nil, err
+		}
+		return encodeNumPacked(v, t, opts)
 
 	case KindBytes:
-		return encodeBytesPacked(v)
+		return encodeBytesPacked(v, opts)
 
 	case KindFixedBytes, KindFunction:
-		return encodeFixedBytesPacked(v,t)
+		return encodeFixedBytesPacked(v, t, opts)
+
+	case KindFixedPoint:
+		n, err := encodeFixed(v, t)
+		if err != nil {
+			return nil, err
+		}
+		return encodeIntPacked(n, fixedIntType(t), opts)
 
 	default:
 		return nil, fmt.Errorf("encoding not available for type '%s'", t.Kind())
 	}
 }
 
-func encodeSliceAndArrayPacked(v reflect.Value, t *Type) ([]byte, error) {
+// checkPackedList checks that the go value is a list compatible
+// with the slice or array type
+func checkPackedList(v reflect.Value, t *Type) error {
 	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
-		return nil, encodeErr(v, t.Kind().String())
+		return encodeErr(v, t.Kind().String())
 	}
 
 	if v.Kind() == reflect.Array && t.Kind() != KindArray {
-		return nil, fmt.Errorf("expected array")
+		return fmt.Errorf("expected array")
 	} else if v.Kind() == reflect.Slice && t.Kind() != KindSlice {
-		return nil, fmt.Errorf("expected slice")
+		return fmt.Errorf("expected slice")
 	}
 
 	if t.Kind() == KindArray && t.Size() != v.Len() {
-		return nil, fmt.Errorf("array len incompatible")
+		return fmt.Errorf("array len incompatible")
 	}
+	return nil
+}
+
+// padArrayElemPacked pads the encoding of an elementary value of an
+// array to 32 bytes like in the abi.encodePacked function of solidity
+func padArrayElemPacked(val []byte, t *Type) []byte {
+	switch t.Kind() {
+	case KindInt, KindFixedPoint:
+		if t.Kind() == KindFixedPoint && !t.signed {
+			return leftPad(val, 32)
+		}
+		// sign extend the negative numbers
+		res := leftPad(val, 32)
+		if len(val) < 32 && len(val) > 0 && val[0]&0x80 != 0 {
+			for i := 0; i < 32-len(val); i++ {
+				res[i] = 0xff
+			}
+		}
+		return res
+
+	case KindUInt, KindBool, KindAddress:
+		return leftPad(val, 32)
+
+	case KindFixedBytes, KindFunction:
+		return rightPad(val, 32)
 
-	var ret, tail []byte
+	default:
+		return val
+	}
+}
 
+// encodeUint64sPacked writes a list of uint64 values widened to size bytes
+// into a single preallocated buffer, skipping the per element big.Int
+// conversion of the generic path
+func encodeUint64sPacked(v reflect.Value, size int) []byte {
+	buf := make([]byte, v.Len()*size)
 	for i := 0; i < v.Len(); i++ {
-		val, err := encodePacked(v.Index(i), t.Elem())
-		if err != nil {
-			return nil, err
-		}
-		ret = append(ret, val...)
+		offset := (i+1)*size - 8
+		binary.BigEndian.PutUint64(buf[offset:offset+8], v.Index(i).Uint())
 	}
-	return append(ret, tail...), nil
+	return buf
 }
 
-func encodeTuplePacked(v reflect.Value, t *Type) ([]byte, error) {
+// tupleValues returns the values of the tuple elements in order. The value
+// can be a list, a map indexed by the element names (or positions for unnamed
+// elements) or a struct. Lists longer than the tuple are accepted and only
+// the first values are used
+func tupleValues(v reflect.Value, t *Type) ([]reflect.Value, error) {
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
@@ -104,10 +815,9 @@ func encodeTuplePacked(v reflect.Value, t *Type) ([]byte, error) {
 		return nil, fmt.Errorf("expected at least the same length")
 	}
 
-	var ret, tail []byte
-	var aux reflect.Value
-
+	values := make([]reflect.Value, len(t.TupleElems()))
 	for i, elem := range t.TupleElems() {
+		var aux reflect.Value
 		if isList {
 			aux = v.Index(i)
 		} else {
@@ -120,23 +830,33 @@ func encodeTuplePacked(v reflect.Value, t *Type) ([]byte, error) {
 		if aux.Kind() == reflect.Invalid {
 			return nil, fmt.Errorf("cannot get key %s", elem.Name)
 		}
-
-		val, err := encodePacked(aux, elem.Elem)
-		if err != nil {
-			return nil, err
-		}
-		ret = append(ret, val...)
+		values[i] = aux
 	}
-
-	return append(ret, tail...), nil
+	return values, nil
 }
 
-func encodeFixedBytesPacked(v reflect.Value, t *Type) ([]byte, error) {
+func encodeFixedBytesPacked(v reflect.Value, t *Type, opts *EncodeOptions) ([]byte, error) {
+	if v.Type() == bigIntT {
+		if !opts.NumericBytes {
+			return nil, fmt.Errorf("cannot encode *big.Int as %s without the NumericBytes option", t.String())
+		}
+		n := v.Interface().(*big.Int)
+		if n.Sign() < 0 || n.BitLen() > t.Size()*8 {
+			return nil, fmt.Errorf("value %s out of range for %s", n.String(), t.String())
+		}
+		return toUSize(n, t.Size()*8), nil
+	}
 	if v.Kind() == reflect.Array {
 		v = convertArrayToBytes(v)
 	}
+	if v.Kind() == reflect.String && opts.TextBytes {
+		if v.Len() > t.Size() {
+			return nil, fmt.Errorf("text '%s' is longer than %d bytes", v.String(), t.Size())
+		}
+		return rightPad([]byte(v.String()), t.Size()), nil
+	}
 	if v.Kind() == reflect.String {
-		value, err := decodeHex(v.String())
+		value, err := bytesFromString(v.String(), opts)
 		if err != nil {
 			return nil, err
 		}
@@ -146,7 +866,11 @@ func encodeFixedBytesPacked(v reflect.Value, t *Type) ([]byte, error) {
 	return rightPad(v.Bytes(), t.Size()), nil
 }
 
-func encodeAddressPacked(v reflect.Value) ([]byte, error) {
+func encodeAddressPacked(v reflect.Value, opts *EncodeOptions) ([]byte, error) {
+	if v.Type().Implements(addressProviderT) {
+		addr := v.Interface().(AddressProvider).Address()
+		return addr[:], nil
+	}
 	if v.Kind() == reflect.Array {
 		v = convertArrayToBytes(v)
 	}
@@ -155,26 +879,58 @@ func encodeAddressPacked(v reflect.Value) ([]byte, error) {
 		if err := addr.UnmarshalText([]byte(v.String())); err != nil {
 			return nil, err
 		}
+		if opts.ValidateChecksum {
+			if err := validateChecksum(v.String(), addr); err != nil {
+				return nil, err
+			}
+		}
 		v = reflect.ValueOf(addr.Bytes())
 	}
 	return v.Bytes(), nil
 }
 
-func encodeBytesPacked(v reflect.Value) ([]byte, error) {
+func encodeBytesPacked(v reflect.Value, opts *EncodeOptions) ([]byte, error) {
+	if v.Kind() == reflect.Func {
+		// lazy values are only computed at encode time
+		provider, ok := v.Interface().(func() ([]byte, error))
+		if !ok {
+			return nil, encodeErr(v, "bytes")
+		}
+		buf, err := provider()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bytes value: %v", err)
+		}
+		return buf, nil
+	}
 	if v.Kind() == reflect.Array {
 		v = convertArrayToBytes(v)
 	}
 	if v.Kind() == reflect.String {
-		value, err := decodeHex(v.String())
+		value, err := bytesFromString(v.String(), opts)
 		if err != nil {
 			return nil, err
 		}
 
 		v = reflect.ValueOf(value)
 	}
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, encodeErr(v, "bytes")
+	}
 	return v.Bytes(), nil
 }
 
+// bytesFromString decodes the string representation of a bytes value
+func bytesFromString(str string, opts *EncodeOptions) ([]byte, error) {
+	if opts.Base64Bytes && strings.HasPrefix(str, "base64:") {
+		buf, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(str, "base64:"))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode base64: %v", err)
+		}
+		return buf, nil
+	}
+	return decodeHex(str)
+}
+
 func encodeStringPacked(v reflect.Value) ([]byte, error) {
 	if v.Kind() != reflect.String {
 		return nil, encodeErr(v, "string")
@@ -182,46 +938,133 @@ func encodeStringPacked(v reflect.Value) ([]byte, error) {
 	return []byte(v.String()), nil
 }
 
-func encodeNumPacked(v reflect.Value, t *Type) ([]byte, error) {
+func encodeNumPacked(v reflect.Value, t *Type, opts *EncodeOptions) ([]byte, error) {
+	if opts.ParseStringers && isStringer(v) {
+		return encodeNumPacked(reflect.ValueOf(v.Interface().(fmt.Stringer).String()), t, opts)
+	}
+	if n, ok := uint256ToBig(v); ok {
+		return encodeIntPacked(n, t, opts)
+	}
+
 	switch v.Kind() {
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		return toUSize(new(big.Int).SetUint64(v.Uint()),t.Size()), nil
+		return encodeIntPacked(new(big.Int).SetUint64(v.Uint()), t, opts)
 
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		return toUSize(big.NewInt(v.Int()),t.Size()), nil
+		return encodeIntPacked(big.NewInt(v.Int()), t, opts)
 
 	case reflect.Ptr:
-		if v.Type() != bigIntT {
-			return nil, encodeErr(v.Elem(), "number")
+		if v.Type() == bigIntT {
+			return encodeIntPacked(v.Interface().(*big.Int), t, opts)
 		}
-		return toUSize(v.Interface().(*big.Int),256), nil
+		switch v.Type().Elem().Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if v.IsNil() {
+				return nil, fmt.Errorf("cannot encode nil pointer as %s", t.String())
+			}
+			return encodeNumPacked(v.Elem(), t, opts)
+		}
+		return nil, encodeErr(v.Elem(), "number")
 
 	case reflect.Float64:
-		return encodeNumPacked(reflect.ValueOf(int64(v.Float())),t)
+		return encodeNumPacked(reflect.ValueOf(int64(v.Float())), t, opts)
 
 	case reflect.String:
-		n, ok := new(big.Int).SetString(v.String(), 10)
-		if !ok {
-			n, ok = new(big.Int).SetString(v.String()[2:], 16)
-			if !ok {
-				return nil, encodeErr(v, "number")
-			}
+		n, err := parseNumString(v.String())
+		if err != nil {
+			return nil, err
+		}
+		if err := checkIntRange(n, t); err != nil {
+			return nil, err
 		}
-		return encodeNumPacked(reflect.ValueOf(n),t)
+		return encodeNumPacked(reflect.ValueOf(n), t, opts)
 
 	default:
 		return nil, encodeErr(v, "number")
 	}
 }
 
+// encodeIntPacked encodes the number with the size of the integer type.
+// With CheckRange the number has to fit in the type
+func encodeIntPacked(n *big.Int, t *Type, opts *EncodeOptions) ([]byte, error) {
+	if opts.CheckRange {
+		if err := checkIntRange(n, t); err != nil {
+			return nil, err
+		}
+	}
+	return toUSize(n, t.Size()), nil
+}
+
+// checkIntRange returns an error if the number does not fit
+// in the integer type
+func checkIntRange(n *big.Int, t *Type) error {
+	size := uint(t.Size())
+	if t.Kind() == KindUInt {
+		if n.Sign() < 0 || n.BitLen() > int(size) {
+			return fmt.Errorf("value %s out of range for %s", n.String(), t.String())
+		}
+		return nil
+	}
+	max := new(big.Int).Lsh(one, size-1)
+	min := new(big.Int).Neg(max)
+	if n.Cmp(min) < 0 || n.Cmp(max) >= 0 {
+		return fmt.Errorf("value %s out of range for %s", n.String(), t.String())
+	}
+	return nil
+}
+
 func encodeBoolPacked(v reflect.Value) ([]byte, error) {
 	if v.Kind() != reflect.Bool {
 		return nil, encodeErr(v, "bool")
 	}
 	if v.Bool() {
-		return one.Bytes(), nil
+		return []byte{1}, nil
+	}
+	return []byte{0}, nil
+}
+
+// isNil returns whether the value is an invalid value or a nil
+// interface or pointer
+func isNil(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
 	}
-	return zero.Bytes(), nil
+	return false
+}
+
+var stringerT = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+// isStringer returns whether the value implements fmt.Stringer. Big integers
+// are excluded since they are encoded as numbers already
+func isStringer(v reflect.Value) bool {
+	if !v.IsValid() || v.Type() == bigIntT || !v.Type().Implements(stringerT) {
+		return false
+	}
+	return v.Kind() != reflect.Ptr || !v.IsNil()
+}
+
+// EncodeMinimalInt encodes the integer as big endian bytes without any
+// padding, zero is encoded as empty bytes. Signed integers use the
+// minimal two's complement form that keeps the sign bit (i.e. 128 is
+// 0x0080 and -1 is 0xff). The length of the bytes is managed by the caller
+func EncodeMinimalInt(v *big.Int, signed bool) []byte {
+	if v.Sign() == 0 {
+		return []byte{}
+	}
+	if !signed && v.Sign() > 0 {
+		return v.Bytes()
+	}
+
+	// negative numbers are always encoded in two's complement
+	bits := v.BitLen()
+	if v.Sign() < 0 {
+		bits = new(big.Int).Sub(new(big.Int).Neg(v), one).BitLen()
+	}
+	return toUSize(v, (bits/8+1)*8)
 }
 
 func toUSize(n *big.Int, size int) []byte {
@@ -229,10 +1072,24 @@ func toUSize(n *big.Int, size int) []byte {
 	b = b.Set(n)
 
 	if b.Sign() < 0 || b.BitLen() > size {
-		tt   := new(big.Int).Lsh(big.NewInt(1), uint(size))   // 2 ** 256
-		ttm1 := new(big.Int).Sub(tt, big.NewInt(1)) // 2 ** 256 - 1
+		tt := new(big.Int).Lsh(big.NewInt(1), uint(size)) // 2 ** 256
+		ttm1 := new(big.Int).Sub(tt, big.NewInt(1))       // 2 ** 256 - 1
 		b.And(b, ttm1)
 	}
 
-	return leftPad(b.Bytes(), size / 8)
+	return leftPad(b.Bytes(), size/8)
+}
+
+// ConcatPacked concatenates several packed encodings into a single
+// buffer allocated once with the total length
+func ConcatPacked(parts ...[]byte) []byte {
+	size := 0
+	for _, part := range parts {
+		size += len(part)
+	}
+	res := make([]byte, 0, size)
+	for _, part := range parts {
+		res = append(res, part...)
+	}
+	return res
 }
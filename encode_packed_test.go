@@ -0,0 +1,914 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestEncodePacked_NestedStructInMap(t *testing.T) {
+	type Inner struct {
+		Addr  ethgo.Address
+		Value *big.Int
+	}
+
+	typ := MustNewType("tuple(uint256 amount, tuple(address addr, uint96 value) inner)")
+	input := map[string]interface{}{
+		"amount": big.NewInt(1),
+		"inner": Inner{
+			Addr:  ethgo.Address{0x1},
+			Value: big.NewInt(2),
+		},
+	}
+
+	expected := mustDecodeHex("0x" +
+		"0000000000000000000000000000000000000000000000000000000000000001" +
+		"0100000000000000000000000000000000000000" +
+		"000000000000000000000002")
+
+	res, err := EncodePacked(input, typ)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	// pointers to structs are also accepted
+	input["inner"] = &Inner{Addr: ethgo.Address{0x1}, Value: big.NewInt(2)}
+	res, err = EncodePacked(input, typ)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+}
+
+func TestConcatPacked(t *testing.T) {
+	require.Equal(t, []byte{}, ConcatPacked())
+	require.Equal(t, []byte{}, ConcatPacked(nil, nil))
+	require.Equal(t, []byte{0x1, 0x2}, ConcatPacked([]byte{0x1, 0x2}))
+	require.Equal(t, []byte{0x1, 0x2, 0x3, 0x4}, ConcatPacked([]byte{0x1}, nil, []byte{0x2, 0x3}, []byte{0x4}))
+}
+
+func TestEncodePacked_NumberStrings(t *testing.T) {
+	cases := []struct {
+		typ      string
+		input    string
+		expected string
+	}{
+		{"int256", "-0", "0x0000000000000000000000000000000000000000000000000000000000000000"},
+		{"uint8", "+5", "0x05"},
+		{"int16", "-2", "0xfffe"},
+		{"uint16", "0x0102", "0x0102"},
+		{"int16", "-0x10", "0xfff0"},
+		{
+			"uint256",
+			"115792089237316195423570985008687907853269984665640564039457584007913129639935",
+			"0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		},
+	}
+
+	for _, c := range cases {
+		res, err := EncodePacked(c.input, MustNewType(c.typ))
+		require.NoError(t, err)
+		require.Equal(t, c.expected, encodeHex(res))
+	}
+
+	invalid := []struct {
+		typ   string
+		input string
+	}{
+		{"uint256", "115792089237316195423570985008687907853269984665640564039457584007913129639936"},
+		{"uint8", "256"},
+		{"uint8", "-1"},
+		{"int8", "128"},
+		{"int8", "-129"},
+		{"uint256", ""},
+		{"uint256", "x"},
+		{"uint256", "--1"},
+		{"uint256", "abc"},
+	}
+	for _, c := range invalid {
+		_, err := EncodePacked(c.input, MustNewType(c.typ))
+		require.Error(t, err, c.input)
+	}
+}
+
+type stringerNum struct {
+	val int
+}
+
+func (s stringerNum) String() string {
+	return strconv.Itoa(s.val)
+}
+
+func TestEncodePacked_Stringer(t *testing.T) {
+	typ := MustNewType("uint256")
+
+	// stringers are only parsed when enabled
+	_, err := EncodePacked(stringerNum{42}, typ)
+	require.Error(t, err)
+
+	res, err := EncodePackedWithOptions(stringerNum{42}, typ, &EncodeOptions{ParseStringers: true})
+	require.NoError(t, err)
+	require.Equal(t, toUSize(big.NewInt(42), 256), res)
+
+	// big integers keep being encoded as numbers
+	res, err = EncodePackedWithOptions(big.NewInt(42), typ, &EncodeOptions{ParseStringers: true})
+	require.NoError(t, err)
+	require.Equal(t, toUSize(big.NewInt(42), 256), res)
+}
+
+func TestEncodePacked_Base64Bytes(t *testing.T) {
+	opts := &EncodeOptions{Base64Bytes: true}
+
+	res, err := EncodePackedWithOptions("base64:AQID", MustNewType("bytes"), opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2, 0x3}, res)
+
+	res, err = EncodePackedWithOptions("0x010203", MustNewType("bytes"), opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2, 0x3}, res)
+
+	res, err = EncodePackedWithOptions("base64:AQI=", MustNewType("bytes4"), opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2, 0x0, 0x0}, res)
+
+	// base64 is only accepted when enabled
+	_, err = EncodePacked("base64:AQID", MustNewType("bytes"))
+	require.Error(t, err)
+
+	_, err = EncodePackedWithOptions("base64:!!", MustNewType("bytes"), opts)
+	require.Error(t, err)
+}
+
+func TestEncodePacked_FastPath(t *testing.T) {
+	cases := []struct {
+		typ   string
+		input interface{}
+	}{
+		{"uint64", uint64(0x0102030405060708)},
+		{"uint256", uint64(1)},
+		{"uint32", uint64(0x0102030405060708)},
+		{"int64", uint64(5)},
+		{"uint128", big.NewInt(300)},
+		{"int24", big.NewInt(-1)},
+		{"address", ethgo.Address{0x1, 0x2}},
+		{"bool", true},
+		{"bool", false},
+		{"bytes", []byte{0x1, 0x2}},
+		{"string", "hello"},
+		{"bytes32", [32]byte{0x1, 0x2}},
+		{"bytes32", ethgo.Hash{0x3}},
+	}
+
+	opts := &EncodeOptions{}
+	for _, c := range cases {
+		typ := MustNewType(c.typ)
+
+		res, ok, err := encodePackedFast(c.input, typ)
+		require.True(t, ok)
+		require.NoError(t, err)
+
+		expected, err := encodePacked(reflect.ValueOf(c.input), typ, opts)
+		require.NoError(t, err)
+		require.Equal(t, expected, res, c.typ)
+	}
+
+	// other values use the reflection path
+	_, ok, _ := encodePackedFast(uint8(1), MustNewType("uint8"))
+	require.False(t, ok)
+
+	_, ok, _ = encodePackedFast("0x01", MustNewType("bytes"))
+	require.False(t, ok)
+
+	_, ok, _ = encodePackedFast([32]byte{}, MustNewType("bytes16"))
+	require.False(t, ok)
+
+	// the result does not share memory with the input
+	in := []byte{0x1, 0x2}
+	res, err := EncodePacked(in, MustNewType("bytes"))
+	require.NoError(t, err)
+	res[0] = 0x9
+	require.Equal(t, []byte{0x1, 0x2}, in)
+}
+
+func BenchmarkEncodePacked_Uint64(b *testing.B) {
+	typ := MustNewType("uint256")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodePacked(uint64(i), typ); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodePacked_Uint64Reflect(b *testing.B) {
+	typ := MustNewType("uint256")
+	opts := &EncodeOptions{}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := encodePacked(reflect.ValueOf(uint64(i)), typ, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodePacked_Uint64Slice(t *testing.T) {
+	values := []uint64{0, 1, 255, math.MaxUint64}
+
+	generic := make([]interface{}, len(values))
+	for i, v := range values {
+		generic[i] = v
+	}
+
+	cases := []struct {
+		typ     string
+		generic interface{}
+		input   interface{}
+	}{
+		{"uint256[]", generic, values},
+		{"uint64[]", generic, values},
+		{"uint128[4]", [4]interface{}{generic[0], generic[1], generic[2], generic[3]}, [4]uint64{values[0], values[1], values[2], values[3]}},
+	}
+	for _, c := range cases {
+		expected, err := EncodePacked(c.generic, MustNewType(c.typ))
+		require.NoError(t, err)
+
+		found, err := EncodePacked(c.input, MustNewType(c.typ))
+		require.NoError(t, err)
+		require.Equal(t, expected, found)
+	}
+}
+
+func BenchmarkEncodePacked_Uint64Slice(b *testing.B) {
+	typ := MustNewType("uint256[]")
+	values := make([]uint64, 100000)
+	for i := range values {
+		values[i] = uint64(i)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodePacked(values, typ); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodePacked_NilElements(t *testing.T) {
+	_, err := EncodePacked([]interface{}{big.NewInt(1), nil}, MustNewType("uint256[]"))
+	require.EqualError(t, err, "nil value at index 1")
+
+	var num *big.Int
+	_, err = EncodePacked([]*big.Int{num}, MustNewType("uint256[]"))
+	require.EqualError(t, err, "nil value at index 0")
+
+	_, err = EncodePacked(nil, MustNewType("uint256"))
+	require.Error(t, err)
+
+	_, err = EncodePacked(map[string]interface{}{"a": nil}, MustNewType("tuple(uint256 a)"))
+	require.Error(t, err)
+}
+
+func TestEncodePacked_TupleFromLongerList(t *testing.T) {
+	typ := MustNewType("tuple(uint8,uint8,uint8)")
+
+	res, err := EncodePacked([]interface{}{uint8(1), uint8(2), uint8(3), "ignored", nil}, typ)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2, 0x3}, res)
+
+	res, err = EncodePacked([4]uint8{1, 2, 3, 4}, typ)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2, 0x3}, res)
+
+	_, err = EncodePacked([]interface{}{uint8(1), uint8(2)}, typ)
+	require.Error(t, err)
+}
+
+func TestEncodePacked_TextBytes(t *testing.T) {
+	opts := &EncodeOptions{TextBytes: true}
+
+	res, err := EncodePackedWithOptions("DAI", MustNewType("bytes32"), opts)
+	require.NoError(t, err)
+	require.Equal(t, rightPad([]byte("DAI"), 32), res)
+
+	_, err = EncodePackedWithOptions("DAI", MustNewType("bytes2"), opts)
+	require.Error(t, err)
+
+	// by default strings are decoded as hex
+	_, err = EncodePacked("DAI", MustNewType("bytes32"))
+	require.Error(t, err)
+}
+
+func TestEncodePacked_PreEncoded(t *testing.T) {
+	typ := MustNewType("tuple(address a, uint16 b, string c)")
+
+	cached, err := EncodePacked(ethgo.Address{0x1}, MustNewType("address"))
+	require.NoError(t, err)
+
+	res, err := EncodePacked(map[string]interface{}{
+		"a": PreEncoded(cached),
+		"b": uint16(1),
+		"c": "hello",
+	}, typ)
+	require.NoError(t, err)
+
+	expected, err := EncodePacked(map[string]interface{}{
+		"a": ethgo.Address{0x1},
+		"b": uint16(1),
+		"c": "hello",
+	}, typ)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+}
+
+func TestEncodePacked_BytesProvider(t *testing.T) {
+	typ := MustNewType("tuple(uint16 a, bytes b)")
+
+	called := false
+	provider := func() ([]byte, error) {
+		called = true
+		return []byte{0x1, 0x2, 0x3}, nil
+	}
+
+	res, err := EncodePacked(map[string]interface{}{
+		"a": uint16(1),
+		"b": provider,
+	}, typ)
+	require.NoError(t, err)
+	require.True(t, called)
+	require.Equal(t, []byte{0x0, 0x1, 0x1, 0x2, 0x3}, res)
+
+	_, err = EncodePacked(map[string]interface{}{
+		"a": uint16(1),
+		"b": func() ([]byte, error) { return nil, fmt.Errorf("not available") },
+	}, typ)
+	require.EqualError(t, err, "b: failed to get bytes value: not available")
+
+	// the providers of a list of values are invoked once
+	calls := 0
+	res, err = EncodePackedValues([]*Type{MustNewType("bytes")}, func() ([]byte, error) {
+		calls++
+		return []byte{0x4}, nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, calls)
+	require.Equal(t, []byte{0x4}, res)
+}
+
+func TestEncodePacked_IntPointers(t *testing.T) {
+	num := uint64(258)
+	res, err := EncodePacked(&num, MustNewType("uint16"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2}, res)
+
+	res, err = EncodePacked(map[string]interface{}{"a": &num}, MustNewType("tuple(uint32 a)"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x0, 0x0, 0x1, 0x2}, res)
+
+	var nilInt *int
+	_, err = EncodePacked(nilInt, MustNewType("int256"))
+	require.Error(t, err)
+
+	_, err = encodeNumPacked(reflect.ValueOf(nilInt), MustNewType("int256"), &EncodeOptions{})
+	require.EqualError(t, err, "cannot encode nil pointer as int256")
+}
+
+func TestPackedSizeArgs(t *testing.T) {
+	types := []*Type{
+		MustNewType("address"),
+		MustNewType("uint16[]"),
+		MustNewType("string"),
+		MustNewType("bytes"),
+		MustNewType("tuple(bool a, bytes b)"),
+	}
+	values := []interface{}{
+		ethgo.Address{0x1},
+		[]uint16{1, 2, 3},
+		"hello",
+		"0x010203",
+		map[string]interface{}{"a": true, "b": []byte{0x1, 0x2}},
+	}
+
+	size, err := PackedSizeArgs(types, values)
+	require.NoError(t, err)
+
+	res, err := EncodePackedArgs(types, values)
+	require.NoError(t, err)
+	require.Equal(t, len(res), size)
+	require.Equal(t, 20+96+5+3+3, size)
+
+	size, err = PackedSize(values[1], types[1])
+	require.NoError(t, err)
+	require.Equal(t, 96, size)
+
+	_, err = PackedSizeArgs(types[:1], values)
+	require.Error(t, err)
+
+	// the size of lazy bytes is only known once they are encoded
+	lazy := func() ([]byte, error) { return []byte{0x1}, nil }
+	_, err = PackedSizeArgs([]*Type{MustNewType("bytes")}, []interface{}{lazy})
+	require.ErrorIs(t, err, ErrLazySize)
+
+	res, err = EncodePackedArgs([]*Type{MustNewType("bytes")}, []interface{}{lazy})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1}, res)
+}
+
+func TestEncodePacked_Label(t *testing.T) {
+	typ := MustNewType("uint8")
+	typ.SetLabel("Amount")
+	require.Equal(t, "Amount", typ.Label())
+
+	_, err := EncodePacked("300", typ)
+	require.EqualError(t, err, "Amount (uint8): value 300 out of range for uint8")
+
+	// labels of the tuple elements are used for nested values
+	tuple := MustNewType("tuple(address to, uint8 amount)")
+	tuple.TupleElems()[1].Elem.SetLabel("Amount")
+
+	_, err = EncodePacked(map[string]interface{}{"to": ethgo.Address{}, "amount": "-1"}, tuple)
+	require.EqualError(t, err, "amount: Amount (uint8): value -1 out of range for uint8")
+
+	// and keep the location of the value in nested tuples and arrays
+	nested := MustNewType("tuple(tuple(address to, uint8 amount)[] items)")
+	nested.TupleElems()[0].Elem.Elem().TupleElems()[1].Elem.SetLabel("Amount")
+
+	_, err = EncodePacked(map[string]interface{}{
+		"items": []map[string]interface{}{
+			{"to": ethgo.Address{}, "amount": "1"},
+			{"to": ethgo.Address{}, "amount": "300"},
+		},
+	}, nested)
+	require.EqualError(t, err, "items[1].amount: Amount (uint8): value 300 out of range for uint8")
+
+	flag := MustNewType("tuple(address to, bool ok)")
+	flag.TupleElems()[1].Elem.SetLabel("Ok")
+
+	_, err = DecodePacked(flag, append(make([]byte, 20), 0x5))
+	require.EqualError(t, err, "ok: Ok (bool): bad boolean")
+
+	// without label the error is unchanged
+	_, err = EncodePacked("300", MustNewType("uint8"))
+	require.EqualError(t, err, "value 300 out of range for uint8")
+}
+
+func TestEncodePacked_NumericBytes(t *testing.T) {
+	typ := MustNewType("bytes32")
+	opts := &EncodeOptions{NumericBytes: true}
+
+	res, err := EncodePackedWithOptions(big.NewInt(255), typ, opts)
+	require.NoError(t, err)
+	require.Equal(t, append(make([]byte, 31), 0xff), res)
+
+	res, err = EncodePackedWithOptions(big.NewInt(258), MustNewType("bytes2"), opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2}, res)
+
+	_, err = EncodePackedWithOptions(big.NewInt(65536), MustNewType("bytes2"), opts)
+	require.Error(t, err)
+
+	_, err = EncodePackedWithOptions(big.NewInt(-1), typ, opts)
+	require.Error(t, err)
+
+	// disabled by default
+	_, err = EncodePacked(big.NewInt(255), typ)
+	require.Error(t, err)
+}
+
+func TestEncodePackedTyped(t *testing.T) {
+	res, err := EncodePackedTyped("uint256[]", []*big.Int{big.NewInt(1), big.NewInt(2)})
+	require.NoError(t, err)
+	require.Equal(t, append(append(make([]byte, 31), 0x1), append(make([]byte, 31), 0x2)...), res)
+
+	value := []interface{}{ethgo.Address{0x1}, big.NewInt(2)}
+	res, err = EncodePackedTyped("(address,uint256)", value)
+	require.NoError(t, err)
+
+	expected, err := EncodePacked(value, MustNewType("tuple(address,uint256)"))
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	// the second call uses the cached type
+	res, err = EncodePackedTyped("(address,uint256)", value)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	_, err = EncodePackedTyped("uint256[", value)
+	require.Error(t, err)
+}
+
+func TestEncodeMinimalInt(t *testing.T) {
+	cases := []struct {
+		num      int64
+		signed   bool
+		expected string
+	}{
+		{0, false, "0x"},
+		{0, true, "0x"},
+		{255, false, "0xff"},
+		{255, true, "0x00ff"},
+		{256, false, "0x0100"},
+		{256, true, "0x0100"},
+		{127, true, "0x7f"},
+		{-1, true, "0xff"},
+		{-128, true, "0x80"},
+		{-129, true, "0xff7f"},
+		{-256, true, "0xff00"},
+	}
+	for _, c := range cases {
+		res := EncodeMinimalInt(big.NewInt(c.num), c.signed)
+		require.Equal(t, c.expected, encodeHex(res), c.num)
+		require.Equal(t, big.NewInt(c.num), DecodeMinimalInt(res, c.signed), c.num)
+	}
+}
+
+func TestEncodePackedSlice(t *testing.T) {
+	values := []uint64{1, 2, math.MaxUint64}
+
+	res, err := EncodePackedSlice(values, MustNewType("uint256"))
+	require.NoError(t, err)
+
+	expected, err := EncodePacked([]interface{}{uint64(1), uint64(2), uint64(math.MaxUint64)}, MustNewType("uint256[]"))
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	// generic path
+	res, err = EncodePackedSlice([]*big.Int{big.NewInt(1), big.NewInt(2)}, MustNewType("uint16"))
+	require.NoError(t, err)
+	require.Equal(t, append(leftPad([]byte{0x1}, 32), leftPad([]byte{0x2}, 32)...), res)
+
+	_, err = EncodePackedSlice([][]uint8{{1, 2}, {3, 4}}, MustNewType("uint8[2]"))
+	require.Error(t, err) // slices are not arrays
+
+	res, err = EncodePackedSlice([][2]uint8{{1, 2}, {3, 4}}, MustNewType("uint8[2]"))
+	require.NoError(t, err)
+	require.Len(t, res, 4*32)
+	require.Equal(t, byte(0x4), res[127])
+
+	// the go type is not compatible with the element type
+	_, err = EncodePackedSlice([]bool{true}, MustNewType("uint256"))
+	require.EqualError(t, err, "go type bool cannot be encoded as uint256")
+
+	_, err = EncodePackedSlice([]string{"a"}, MustNewType("address[]"))
+	require.Error(t, err)
+	// tight arrays
+	tight := &EncodeOptions{TightArrays: true}
+	res, err = EncodePackedSliceWithOptions([]*big.Int{big.NewInt(1), big.NewInt(2)}, MustNewType("uint16"), tight)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x0, 0x1, 0x0, 0x2}, res)
+
+	res, err = EncodePackedSliceWithOptions([]uint64{1, 2}, MustNewType("uint64"), tight)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2}, res)
+}
+
+func BenchmarkEncodePackedSlice_Uint64(b *testing.B) {
+	typ := MustNewType("uint256")
+	values := make([]uint64, 1000)
+	for i := range values {
+		values[i] = uint64(i)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodePackedSlice(values, typ); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodePackedSlice_BigInt(b *testing.B) {
+	typ := MustNewType("uint256")
+	values := make([]*big.Int, 1000)
+	for i := range values {
+		values[i] = big.NewInt(int64(i))
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodePackedSlice(values, typ); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+type resolvedName struct {
+	name string
+	addr ethgo.Address
+}
+
+func (r *resolvedName) Address() ethgo.Address {
+	return r.addr
+}
+
+func TestEncodePacked_AddressProvider(t *testing.T) {
+	name := &resolvedName{name: "vitalik.eth", addr: ethgo.Address{0x1}}
+
+	res, err := EncodePacked(name, MustNewType("address"))
+	require.NoError(t, err)
+	require.Equal(t, name.addr[:], res)
+
+	res, err = EncodePacked(map[string]interface{}{"a": name, "b": uint8(2)}, MustNewType("tuple(address a, uint8 b)"))
+	require.NoError(t, err)
+	require.Equal(t, append(name.addr.Bytes(), 0x2), res)
+
+	res, err = EncodePackedSlice([]*resolvedName{name}, MustNewType("address"))
+	require.NoError(t, err)
+	require.Equal(t, leftPad(name.addr[:], 32), res)
+}
+
+func TestEncodePacked_ArrayPadding(t *testing.T) {
+	// like solidity, the array elements are padded to 32 bytes
+	res, err := EncodePacked([]uint8{1, 2}, MustNewType("uint8[]"))
+	require.NoError(t, err)
+	require.Equal(t, append(leftPad([]byte{0x1}, 32), leftPad([]byte{0x2}, 32)...), res)
+
+	// negative numbers are sign extended
+	res, err = EncodePacked([]int8{-1}, MustNewType("int8[]"))
+	require.NoError(t, err)
+	require.Equal(t, bytes.Repeat([]byte{0xff}, 32), res)
+
+	// fixed bytes are padded on the right
+	res, err = EncodePacked([][2]byte{{0x1, 0x2}}, MustNewType("bytes2[]"))
+	require.NoError(t, err)
+	require.Equal(t, rightPad([]byte{0x1, 0x2}, 32), res)
+
+	val, err := DecodePacked(MustNewType("int8[1]"), bytes.Repeat([]byte{0xff}, 32))
+	require.NoError(t, err)
+	require.Equal(t, [1]int8{-1}, val)
+
+	// tight arrays keep the packed size of the elements
+	typ := MustNewType("uint16[2]")
+	res, err = EncodePackedWithOptions([2]uint16{1, 2}, typ, &EncodeOptions{TightArrays: true})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x0, 0x1, 0x0, 0x2}, res)
+
+	val, err = DecodePackedWithOptions(typ, res, &DecodeOptions{TightArrays: true})
+	require.NoError(t, err)
+	require.Equal(t, [2]uint16{1, 2}, val)
+}
+
+func TestEncodePackedValues(t *testing.T) {
+	types := []*Type{MustNewType("address"), MustNewType("uint16"), MustNewType("string")}
+	addr := ethgo.Address{0x1}
+
+	res, err := EncodePackedValues(types, addr, uint16(2), "abc")
+	require.NoError(t, err)
+
+	expected := append(addr.Bytes(), 0x0, 0x2)
+	expected = append(expected, []byte("abc")...)
+	require.Equal(t, expected, res)
+
+	_, err = EncodePackedValues(types, addr, uint16(2))
+	require.EqualError(t, err, "expected 3 values but found 2")
+}
+
+func TestEncodePacked_CheckRange(t *testing.T) {
+	opts := &EncodeOptions{CheckRange: true}
+
+	// by default the integers are truncated
+	res, err := EncodePacked(uint64(300), MustNewType("uint8"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x2c}, res)
+
+	res, err = EncodePacked(big.NewInt(300), MustNewType("uint8"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x2c}, res)
+
+	// but the numeric strings are always checked
+	_, err = EncodePacked("300", MustNewType("uint8"))
+	require.EqualError(t, err, "value 300 out of range for uint8")
+
+	_, err = EncodePackedWithOptions(uint64(300), MustNewType("uint8"), opts)
+	require.EqualError(t, err, "value 300 out of range for uint8")
+
+	_, err = EncodePackedWithOptions(big.NewInt(-1), MustNewType("uint256"), opts)
+	require.EqualError(t, err, "value -1 out of range for uint256")
+
+	_, err = EncodePackedWithOptions(int16(128), MustNewType("int8"), opts)
+	require.EqualError(t, err, "value 128 out of range for int8")
+
+	_, err = EncodePackedWithOptions([]int{1, 256}, MustNewType("uint8[]"), opts)
+	require.EqualError(t, err, "[1]: value 256 out of range for uint8")
+
+	res, err = EncodePackedWithOptions(int8(-128), MustNewType("int8"), opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x80}, res)
+}
+
+func TestEncodePackedToHex(t *testing.T) {
+	typ := MustNewType("tuple(uint16 a, bool b)")
+	value := map[string]interface{}{"a": uint16(258), "b": true}
+
+	str, err := EncodePackedToHex(value, typ)
+	require.NoError(t, err)
+	require.Equal(t, "0x010201", str)
+
+	res, err := DecodePackedFromHex(typ, str)
+	require.NoError(t, err)
+	require.Equal(t, value, res)
+
+	// the prefix is optional
+	res, err = DecodePackedFromHex(typ, "010201")
+	require.NoError(t, err)
+	require.Equal(t, value, res)
+
+	_, err = DecodePackedFromHex(typ, "0x01020")
+	require.Error(t, err)
+
+	_, err = EncodePackedToHex("a", typ)
+	require.Error(t, err)
+}
+
+func TestAppendPacked(t *testing.T) {
+	cases := []struct {
+		typ string
+		val interface{}
+	}{
+		{"uint64", uint64(1)},
+		{"uint24", uint64(0x1020304)},
+		{"uint256", uint64(math.MaxUint64)},
+		{"uint256", big.NewInt(1000)},
+		{"int16", big.NewInt(-2)},
+		{"uint8", big.NewInt(300)},
+		{"address", ethgo.Address{0x1}},
+		{"bool", true},
+		{"bytes", []byte{0x1, 0x2}},
+		{"string", "hello"},
+		{"uint8[2]", [2]uint8{1, 2}},
+		{"tuple(uint8,bool)", []interface{}{uint8(1), false}},
+	}
+
+	buf := []byte{0xaa}
+	expected := []byte{0xaa}
+	for _, c := range cases {
+		typ := MustNewType(c.typ)
+
+		enc, err := EncodePacked(c.val, typ)
+		require.NoError(t, err, c.typ)
+		expected = append(expected, enc...)
+
+		buf, err = AppendPacked(buf, c.val, typ)
+		require.NoError(t, err, c.typ)
+	}
+	require.Equal(t, expected, buf)
+
+	// the buffer is returned unchanged on errors
+	res, err := AppendPacked(buf, "a", MustNewType("bool"))
+	require.Error(t, err)
+	require.Equal(t, buf, res)
+
+	// reused buffers are cleared
+	dirty := bytes.Repeat([]byte{0xff}, 64)
+	res, err = AppendPacked(dirty[:0], big.NewInt(1), MustNewType("uint256"))
+	require.NoError(t, err)
+	require.Equal(t, append(make([]byte, 31), 0x1), res)
+
+	// no allocations with enough capacity
+	typ := MustNewType("uint256")
+	dst := make([]byte, 0, 32)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst, _ = AppendPacked(dst[:0], uint64(1), typ)
+	})
+	require.Equal(t, float64(0), allocs)
+}
+
+// benchmarkPackedTuple is a tuple with the common types of the packed payloads
+var benchmarkPackedTuple = MustNewType("tuple(address a, uint256 b, bytes32 c, uint64[] d, string e)")
+
+func benchmarkPackedValue() map[string]interface{} {
+	return map[string]interface{}{
+		"a": ethgo.HexToAddress("0x1"),
+		"b": big.NewInt(1000),
+		"c": [32]byte{0x1},
+		"d": []uint64{1, 2, 3, 4},
+		"e": "hello world",
+	}
+}
+
+func BenchmarkEncodePacked_Tuple(b *testing.B) {
+	value := benchmarkPackedValue()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodePacked(value, benchmarkPackedTuple); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncodePacked_AddressArray(b *testing.B) {
+	typ := MustNewType("address[]")
+	values := make([]ethgo.Address, 100)
+	for i := range values {
+		values[i][19] = byte(i)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodePacked(values, typ); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkAppendPacked_Tuple(b *testing.B) {
+	value := benchmarkPackedValue()
+	buf := make([]byte, 0, 1024)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = AppendPacked(buf[:0], value, benchmarkPackedTuple); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestEncodePackedAs(t *testing.T) {
+	res, err := EncodePackedAs(uint16(0x102), MustNewType("uint16"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1, 0x2}, res)
+
+	_, err = EncodePackedAs("abc", MustNewType("bool"))
+	require.EqualError(t, err, "go type string cannot be encoded as bool")
+}
+
+func TestEncodePacked_ValidateChecksum(t *testing.T) {
+	typ := MustNewType("address")
+	opts := &EncodeOptions{ValidateChecksum: true}
+
+	valid := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	res, err := EncodePackedWithOptions(valid, typ, opts)
+	require.NoError(t, err)
+	require.Equal(t, ethgo.HexToAddress(valid).Bytes(), res)
+
+	// lower case addresses have no checksum
+	_, err = EncodePackedWithOptions(strings.ToLower(valid), typ, opts)
+	require.NoError(t, err)
+
+	invalid := "0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	_, err = EncodePackedWithOptions(invalid, typ, opts)
+	require.ErrorContains(t, err, "invalid checksum for address")
+
+	// without the option the address is encoded regardless of the case
+	_, err = EncodePacked(invalid, typ)
+	require.NoError(t, err)
+}
+
+func TestEncode_NilPolicy(t *testing.T) {
+	var num *big.Int
+	typ := MustNewType("tuple(uint256 a, address b, uint8[2] c, bytes d)")
+	value := map[string]interface{}{"a": num, "b": nil, "c": [2]interface{}{nil, uint8(1)}, "d": nil}
+
+	// errors name the tuple element
+	_, err := Encode(value, typ)
+	require.EqualError(t, err, "nil value for tuple element 'a' of type uint256")
+
+	_, err = EncodePacked(value, typ)
+	require.EqualError(t, err, "a: cannot encode nil value as uint256")
+
+	// the nil values are encoded as zero values
+	zero := &EncodeOptions{NilPolicy: NilPolicyZero}
+	expected := map[string]interface{}{
+		"a": big.NewInt(0),
+		"b": ethgo.Address{},
+		"c": [2]uint8{0, 1},
+		"d": []byte{},
+	}
+
+	res, err := EncodeWithOptions(value, typ, zero)
+	require.NoError(t, err)
+	std, err := Encode(expected, typ)
+	require.NoError(t, err)
+	require.Equal(t, std, res)
+
+	res, err = EncodePackedWithOptions(value, typ, zero)
+	require.NoError(t, err)
+	packed, err := EncodePacked(expected, typ)
+	require.NoError(t, err)
+	require.Equal(t, packed, res)
+
+	res, err = EncodePackedWithOptions(nil, MustNewType("tuple(bool,uint16)[2]"), zero)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 6), res)
+
+	// fixed point values
+	res, err = EncodeWithOptions((*big.Rat)(nil), MustNewType("ufixed128x18"), zero)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 32), res)
+
+	res, err = EncodeWithOptions(map[string]interface{}{"a": nil}, MustNewType("tuple(fixed a)"), zero)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 32), res)
+
+	res, err = EncodePackedWithOptions(map[string]interface{}{"a": nil}, MustNewType("tuple(fixed a)"), zero)
+	require.NoError(t, err)
+	require.Equal(t, make([]byte, 16), res)
+}
@@ -1,6 +1,7 @@
 package abi
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
@@ -757,3 +758,152 @@ func TestEncodingStruct_camcelCase(t *testing.T) {
 		t.Fatal("bad")
 	}
 }
+
+func TestEncodingMapTuple(t *testing.T) {
+	expected := mustDecodeHex("0x" +
+		"0000000000000000000000000000000000000000000000000000000000000040" +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"0000000000000000000000000000000000000000000000000000000000000005" +
+		"68656c6c6f000000000000000000000000000000000000000000000000000000")
+
+	typ := MustNewType("tuple(string name, uint256 amount)")
+	input := map[string]interface{}{
+		"amount": big.NewInt(5),
+		"name":   "hello",
+	}
+
+	encoded, err := Encode(input, typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, encoded) {
+		t.Fatal("bad")
+	}
+
+	// unnamed elements are indexed by position
+	encoded, err = Encode(map[string]interface{}{"0": "hello", "1": big.NewInt(5)}, MustNewType("tuple(string,uint256)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, encoded) {
+		t.Fatal("bad")
+	}
+
+	decoded, err := Decode(typ, encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(input, decoded) {
+		t.Fatal("bad")
+	}
+}
+
+func TestEncodingWordSize(t *testing.T) {
+	opts16 := &EncodeOptions{WordSize: 16}
+	dopts16 := &DecodeOptions{WordSize: 16}
+
+	num := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	typ := MustNewType("uint128")
+
+	encoded, err := EncodeWithOptions(num, typ, opts16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(encoded, bytes.Repeat([]byte{0xff}, 16)) {
+		t.Fatalf("bad: %x", encoded)
+	}
+	decoded, err := DecodeWithOptions(typ, encoded, dopts16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(num, decoded) {
+		t.Fatal("bad")
+	}
+
+	// negative values and dynamic types use the same word size
+	typ = MustNewType("tuple(int128 a, string b, uint8[] c)")
+	input := map[string]interface{}{
+		"a": big.NewInt(-1),
+		"b": "hello",
+		"c": []uint8{1, 2},
+	}
+	encoded, err = EncodeWithOptions(input, typ, opts16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encoded) != 16*8 {
+		t.Fatalf("bad length %d", len(encoded))
+	}
+	decoded, err = DecodeWithOptions(typ, encoded, dopts16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(input, decoded) {
+		t.Fatal("bad")
+	}
+
+	// the default word size is 32 bytes
+	encoded, err = EncodeWithOptions(input, typ, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := Encode(input, typ)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, encoded) {
+		t.Fatal("bad")
+	}
+
+	// types larger than the word are not supported
+	if _, err := EncodeWithOptions(big.NewInt(1), MustNewType("uint256"), opts16); err == nil {
+		t.Fatal("expected an error")
+	}
+	if _, err := DecodeWithOptions(MustNewType("address"), make([]byte, 16), dopts16); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestEncodingTypeMethods(t *testing.T) {
+	typ := MustNewType("tuple(uint256 a, string b, uint16[] c)")
+	input := map[string]interface{}{
+		"a": big.NewInt(1),
+		"b": "hello",
+		"c": []uint16{1, 2},
+	}
+
+	// standard encoding with the head and the offsets of the dynamic tail
+	encoded, err := typ.Encode(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encoded) != 32*8 {
+		t.Fatalf("bad length %d", len(encoded))
+	}
+	decoded, err := typ.Decode(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(input, decoded) {
+		t.Fatal("bad")
+	}
+
+	// packed encoding with the same type
+	typ = MustNewType("tuple(uint256 a, string b)")
+	delete(input, "c")
+
+	encoded, err = typ.EncodePacked(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encoded) != 32+5 {
+		t.Fatalf("bad length %d", len(encoded))
+	}
+	decoded, err = typ.DecodePacked(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(input, decoded) {
+		t.Fatal("bad")
+	}
+}
@@ -0,0 +1,121 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+)
+
+// RegisterEnum registers the names of the values of a solidity enum. The
+// uint8 arguments of an abi with the internal type 'enum <name>' (i.e.
+// 'enum Order.Status') get the names of the enum. Registering an enum
+// without values removes it
+func RegisterEnum(name string, values ...string) {
+	updateRegistry(func(r *registry) {
+		if len(values) == 0 {
+			delete(r.enums, name)
+			return
+		}
+		r.enums[name] = values
+	})
+}
+
+// getEnum returns the names of the values of the enum of an internal type
+func getEnum(itype string) ([]string, bool) {
+	name, ok := strings.CutPrefix(itype, "enum ")
+	if !ok {
+		return nil, false
+	}
+
+	enums := loadRegistry().enums
+	if values, ok := enums[name]; ok {
+		return values, true
+	}
+	// enums declared in a contract are prefixed with its name
+	if indx := strings.LastIndex(name, "."); indx != -1 {
+		values, ok := enums[name[indx+1:]]
+		return values, ok
+	}
+	return nil, false
+}
+
+// NewEnumType returns an uint8 type whose values have the given names,
+// like a solidity enum. Values of the type can be encoded from their
+// names and decoded into them with the EnumNames option
+func NewEnumType(values ...string) *Type {
+	return &Type{kind: KindUInt, size: 8, t: uint8T, enum: values}
+}
+
+// EnumValues returns the names of the values of an enum type or nil if
+// the type is not an enum
+func (t *Type) EnumValues() []string {
+	return t.enum
+}
+
+// encodeEnumName converts the name of an enum value into its number.
+// Numeric values are returned as they are if they are a value of the
+// enum, like solidity which reverts when converting others to an enum
+func encodeEnumName(v reflect.Value, t *Type) (reflect.Value, error) {
+	if t.enum == nil {
+		return v, nil
+	}
+
+	var n *big.Int
+	switch v.Kind() {
+	case reflect.String:
+		for indx, name := range t.enum {
+			if name == v.String() {
+				return reflect.ValueOf(uint8(indx)), nil
+			}
+		}
+		num, err := parseNumString(v.String())
+		if err != nil {
+			return v, fmt.Errorf("unknown value '%s' for enum", v.String())
+		}
+		n = num
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = big.NewInt(v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = new(big.Int).SetUint64(v.Uint())
+
+	case reflect.Ptr:
+		if v.Type() == bigIntT && !v.IsNil() {
+			n = v.Interface().(*big.Int)
+		}
+	}
+	if n != nil && (n.Sign() < 0 || n.Cmp(big.NewInt(int64(len(t.enum)))) >= 0) {
+		return v, fmt.Errorf("invalid value %s for enum with %d values", n, len(t.enum))
+	}
+	return v, nil
+}
+
+// decodeEnumName returns the name of a decoded enum value
+func decodeEnumName(t *Type, val interface{}) (interface{}, error) {
+	n, ok := val.(uint8)
+	if !ok {
+		return val, nil
+	}
+	if int(n) >= len(t.enum) {
+		return nil, fmt.Errorf("invalid value %d for enum with %d values", n, len(t.enum))
+	}
+	return t.enum[n], nil
+}
+
+// enumNamesGoType returns the go type of the values of the type decoded
+// with the names of the enums
+func enumNamesGoType(t *Type) reflect.Type {
+	switch t.kind {
+	case KindSlice:
+		return reflect.SliceOf(enumNamesGoType(t.elem))
+	case KindArray:
+		return reflect.ArrayOf(t.size, enumNamesGoType(t.elem))
+	default:
+		if t.enum != nil {
+			return stringT
+		}
+		return t.t
+	}
+}
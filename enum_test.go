@@ -0,0 +1,95 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnum(t *testing.T) {
+	typ := NewEnumType("Pending", "Filled", "Cancelled")
+	require.Equal(t, "uint8", typ.String())
+	require.Equal(t, []string{"Pending", "Filled", "Cancelled"}, typ.EnumValues())
+
+	// names and numbers are encoded as the same value
+	res, err := EncodePacked("Filled", typ)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1}, res)
+
+	res, err = EncodePacked(uint8(1), typ)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x1}, res)
+
+	_, err = EncodePacked("Unknown", typ)
+	require.ErrorContains(t, err, "unknown value 'Unknown' for enum")
+
+	// numbers out of the enum are rejected like in solidity
+	for _, v := range []interface{}{uint8(7), "7", uint64(3), big.NewInt(3), -1} {
+		_, err = EncodePacked(v, typ)
+		require.ErrorContains(t, err, "for enum with 3 values", v)
+
+		_, err = Encode(v, typ)
+		require.ErrorContains(t, err, "for enum with 3 values", v)
+	}
+
+	std, err := Encode("Cancelled", typ)
+	require.NoError(t, err)
+	require.Equal(t, byte(2), std[31])
+
+	// decoded as numbers unless the names are requested
+	names := &DecodeOptions{EnumNames: true}
+
+	val, err := DecodePacked(typ, []byte{0x1})
+	require.NoError(t, err)
+	require.Equal(t, uint8(1), val)
+
+	val, err = DecodePackedWithOptions(typ, []byte{0x1}, names)
+	require.NoError(t, err)
+	require.Equal(t, "Filled", val)
+
+	val, err = DecodeWithOptions(typ, std, names)
+	require.NoError(t, err)
+	require.Equal(t, "Cancelled", val)
+
+	_, err = DecodePackedWithOptions(typ, []byte{0x3}, names)
+	require.ErrorContains(t, err, "invalid value 3 for enum with 3 values")
+}
+
+func TestEnum_Registered(t *testing.T) {
+	RegisterEnum("Status", "Pending", "Filled", "Cancelled")
+	defer RegisterEnum("Status")
+
+	typ, err := NewTypeFromArgument(&ArgumentStr{
+		Type: "tuple[]",
+		Components: []*ArgumentStr{
+			{Name: "id", Type: "uint256"},
+			{Name: "status", Type: "uint8", InternalType: "enum Order.Status"},
+		},
+	})
+	require.NoError(t, err)
+
+	orders := []map[string]interface{}{
+		{"id": 1, "status": "Filled"},
+		{"id": 2, "status": "Pending"},
+	}
+	input, err := Encode(orders, typ)
+	require.NoError(t, err)
+
+	res, err := DecodeWithOptions(typ, input, &DecodeOptions{EnumNames: true})
+	require.NoError(t, err)
+	list := res.([]map[string]interface{})
+	require.Equal(t, "Filled", list[0]["status"])
+	require.Equal(t, "Pending", list[1]["status"])
+
+	// arrays of enums
+	arr, err := NewSliceType(NewEnumType("A", "B"))
+	require.NoError(t, err)
+
+	input, err = EncodePacked([]string{"B", "A"}, arr)
+	require.NoError(t, err)
+
+	res, err = DecodePackedWithOptions(arr, input, &DecodeOptions{EnumNames: true})
+	require.NoError(t, err)
+	require.Equal(t, []string{"B", "A"}, res)
+}
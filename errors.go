@@ -0,0 +1,158 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrShortInput is the cause of the decoding errors of inputs that
+	// are shorter than the value of the type
+	ErrShortInput = errors.New("short input")
+
+	// ErrInvalidSchema is the cause of the errors of types that cannot
+	// be decoded in packed mode
+	ErrInvalidSchema = errors.New("invalid packed schema")
+
+	// ErrLazySize is the error of the packed size of values with lazy
+	// bytes, whose length is only known once they are encoded
+	ErrLazySize = errors.New("size of lazy bytes value is not known")
+)
+
+// ABIError is the error returned by the packed encoding and decoding
+// with the location and the type of the value that failed
+type ABIError struct {
+	// Op is the operation that failed, encode or decode
+	Op string
+
+	// Path is the location of the value inside the tuples and arrays
+	// (i.e. 'items[1].amount'). It is empty for the top level value
+	Path string
+
+	// Type is the type of the value that failed
+	Type *Type
+
+	// Offset is the position in the input of the value that failed
+	// to decode. It is zero when encoding
+	Offset int
+
+	// Expected and Available are the number of bytes required by the
+	// value and the number of bytes left in the input for ErrShortInput
+	// errors
+	Expected  int
+	Available int
+
+	// Err is the cause of the error
+	Err error
+
+	// rest is the length of the input left when the error was created,
+	// used to compute the offset of the value
+	rest int
+}
+
+// Error implements the error interface. The message has the path of the
+// value and the label of its type if they are set
+// (i.e. 'items[1].amount: Amount (uint256): err')
+func (e *ABIError) Error() string {
+	msg := e.Err.Error()
+	if e.Type != nil && e.Type.label != "" {
+		msg = fmt.Sprintf("%s (%s): %s", e.Type.label, e.Type.String(), msg)
+	}
+	if e.Path != "" {
+		msg = fmt.Sprintf("%s: %s", e.Path, msg)
+	}
+	return msg
+}
+
+// Unwrap returns the cause of the error
+func (e *ABIError) Unwrap() error {
+	return e.Err
+}
+
+// newABIError wraps the error of an operation over a value of type t.
+// Errors that are already an ABIError keep the innermost type
+func newABIError(op string, t *Type, err error) error {
+	var abiErr *ABIError
+	if errors.As(err, &abiErr) {
+		return err
+	}
+	res := &ABIError{Op: op, Type: t, Err: err}
+
+	var lenErr *lengthError
+	if errors.As(err, &lenErr) {
+		res.Expected = lenErr.expected
+		res.Available = lenErr.available
+	}
+	return res
+}
+
+// newDecodeError wraps the error of decoding a value of type t with the
+// length of the input left at the start of the value
+func newDecodeError(t *Type, err error, rest int) error {
+	var abiErr *ABIError
+	if errors.As(err, &abiErr) {
+		return err
+	}
+	res := newABIError("decode", t, err).(*ABIError)
+	res.rest = rest
+	return res
+}
+
+// withOffset sets the offset of the value that failed to decode from
+// the total length of the input
+func withOffset(err error, total int) error {
+	var abiErr *ABIError
+	if errors.As(err, &abiErr) && abiErr.Op == "decode" {
+		abiErr.Offset = total - abiErr.rest
+	}
+	return err
+}
+
+// lengthError is the error of an input shorter than the value to decode
+type lengthError struct {
+	msg       string
+	expected  int
+	available int
+}
+
+func (e *lengthError) Error() string {
+	return e.msg
+}
+
+// Is matches the ErrShortInput sentinel
+func (e *lengthError) Is(target error) bool {
+	return target == ErrShortInput
+}
+
+// schemaError is the error of a type that cannot be decoded in packed mode
+type schemaError struct {
+	msg string
+}
+
+func (e *schemaError) Error() string {
+	return e.msg
+}
+
+// Is matches the ErrInvalidSchema sentinel
+func (e *schemaError) Is(target error) bool {
+	return target == ErrInvalidSchema
+}
+
+// withPath prepends the location of an element (a tuple element name
+// or an array index like '[1]') to the path of the error
+func withPath(err error, elem string) error {
+	var abiErr *ABIError
+	if !errors.As(err, &abiErr) {
+		return err
+	}
+	switch {
+	case abiErr.Path == "":
+		abiErr.Path = elem
+	case strings.HasPrefix(abiErr.Path, "["):
+		abiErr.Path = elem + abiErr.Path
+	default:
+		abiErr.Path = elem + "." + abiErr.Path
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestABIError_Encode(t *testing.T) {
+	typ := MustNewType("tuple(address to, tuple(uint256 id, uint8 amount)[] items)")
+
+	_, err := EncodePacked(map[string]interface{}{
+		"to": ethgo.Address{},
+		"items": []map[string]interface{}{
+			{"id": big.NewInt(1), "amount": uint8(1)},
+			{"id": big.NewInt(2), "amount": "300"},
+		},
+	}, typ)
+	require.EqualError(t, err, "items[1].amount: value 300 out of range for uint8")
+
+	var abiErr *ABIError
+	require.True(t, errors.As(err, &abiErr))
+	require.Equal(t, "encode", abiErr.Op)
+	require.Equal(t, "items[1].amount", abiErr.Path)
+	require.Equal(t, "uint8", abiErr.Type.String())
+	require.EqualError(t, abiErr.Err, "value 300 out of range for uint8")
+}
+
+func TestABIError_Decode(t *testing.T) {
+	typ := MustNewType("tuple(uint8 a, bool[2] flags)")
+
+	input := append([]byte{0x1}, leftPad([]byte{0x1}, 32)...)
+	input = append(input, leftPad([]byte{0x5}, 32)...)
+
+	_, err := DecodePacked(typ, input)
+	require.EqualError(t, err, "flags[1]: bad boolean")
+
+	var abiErr *ABIError
+	require.True(t, errors.As(err, &abiErr))
+	require.Equal(t, "decode", abiErr.Op)
+	require.Equal(t, "flags[1]", abiErr.Path)
+	require.Equal(t, KindBool, abiErr.Type.Kind())
+
+	// top level errors have no path
+	_, err = DecodePacked(MustNewType("bool"), []byte{0x5})
+	require.True(t, errors.As(err, &abiErr))
+	require.Equal(t, "", abiErr.Path)
+	require.EqualError(t, err, "bad boolean")
+}
+
+func TestABIError_OffsetAndLengths(t *testing.T) {
+	typ := MustNewType("tuple(address to, uint8 n, bool[2] flags)")
+
+	input := append(ethgo.Address{0x1}.Bytes(), 0x1)
+	input = append(input, leftPad([]byte{0x1}, 32)...)
+	input = append(input, leftPad([]byte{0x5}, 32)...)
+
+	_, err := DecodePacked(typ, input)
+	require.EqualError(t, err, "flags[1]: bad boolean")
+
+	// the offset is the start of the padded element
+	var abiErr *ABIError
+	require.True(t, errors.As(err, &abiErr))
+	require.Equal(t, 20+1+32, abiErr.Offset)
+
+	// short inputs have the expected and available lengths
+	_, err = DecodePacked(MustNewType("tuple(address to, uint256 amount)"), input[:30])
+	require.ErrorIs(t, err, ErrShortInput)
+	require.True(t, errors.As(err, &abiErr))
+	require.Equal(t, 52, abiErr.Expected)
+	require.Equal(t, 30, abiErr.Available)
+	require.Equal(t, 0, abiErr.Offset)
+
+	_, err = DecodePackedValues([]*Type{MustNewType("address"), MustNewType("uint256")}, input[:30])
+	require.ErrorIs(t, err, ErrShortInput)
+	require.True(t, errors.As(err, &abiErr))
+	require.Equal(t, 32, abiErr.Expected)
+	require.Equal(t, 10, abiErr.Available)
+	require.Equal(t, 20, abiErr.Offset)
+
+	// invalid schemas
+	_, err = DecodePacked(MustNewType("tuple(string a, uint8 b)"), []byte("abc"))
+	require.ErrorIs(t, err, ErrInvalidSchema)
+	require.NotErrorIs(t, err, ErrShortInput)
+}
@@ -0,0 +1,119 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+var ratT = reflect.TypeOf(new(big.Rat))
+
+var fixedRegexp = regexp.MustCompile("^(u?fixed)(?:([[:digit:]]+)x([[:digit:]]+))?$")
+
+// decodeFixedType parses the fixed point types fixed<M>x<N> and
+// ufixed<M>x<N>. It returns false if the string is not a fixed point type
+func decodeFixedType(str string) (*Type, bool, error) {
+	match := fixedRegexp.FindStringSubmatch(str)
+	if len(match) == 0 {
+		return nil, false, nil
+	}
+
+	// fixed and ufixed are aliases for fixed128x18 and ufixed128x18
+	size, decimals := 128, 18
+	if match[2] != "" {
+		var err error
+		if size, err = strconv.Atoi(match[2]); err != nil {
+			return nil, true, fmt.Errorf("failed to parse size '%s': %v", match[2], err)
+		}
+		if decimals, err = strconv.Atoi(match[3]); err != nil {
+			return nil, true, fmt.Errorf("failed to parse decimals '%s': %v", match[3], err)
+		}
+	}
+	if size == 0 || size > 256 || size%8 != 0 {
+		return nil, true, fmt.Errorf("invalid size %d for type %s, it has to be a multiple of 8 up to 256", size, match[1])
+	}
+	if decimals > 80 {
+		return nil, true, fmt.Errorf("invalid decimals %d for type %s, it has to be up to 80", decimals, match[1])
+	}
+	typ := &Type{
+		kind:     KindFixedPoint,
+		size:     size,
+		decimals: decimals,
+		signed:   match[1] == "fixed",
+		t:        ratT,
+	}
+	return typ, true, nil
+}
+
+// Decimals returns the number of decimals of a fixed point type
+func (t *Type) Decimals() int {
+	return t.decimals
+}
+
+// fixedIntType returns the integer type of the scaled values of a
+// fixed point type
+func fixedIntType(t *Type) *Type {
+	if t.signed {
+		return &Type{kind: KindInt, size: t.size, t: bigIntT}
+	}
+	return &Type{kind: KindUInt, size: t.size, t: bigIntT}
+}
+
+// encodeFixed returns the value of a fixed point type multiplied by
+// 10^decimals. The value can be a *big.Rat, a decimal string (i.e. '1.5'),
+// a float or an integer, and it cannot have more decimals than the type
+func encodeFixed(v reflect.Value, t *Type) (*big.Int, error) {
+	var r *big.Rat
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		switch v.Type() {
+		case ratT:
+			r = new(big.Rat).Set(v.Interface().(*big.Rat))
+		case bigIntT:
+			r = new(big.Rat).SetInt(v.Interface().(*big.Int))
+		default:
+			return nil, encodeErr(v.Elem(), "fixed point")
+		}
+
+	case reflect.String:
+		var ok bool
+		if r, ok = new(big.Rat).SetString(v.String()); !ok {
+			return nil, fmt.Errorf("invalid fixed point value '%s'", v.String())
+		}
+
+	case reflect.Float32, reflect.Float64:
+		// use the shortest decimal representation of the float, which
+		// is not a number for NaN and the infinities
+		var ok bool
+		if r, ok = new(big.Rat).SetString(strconv.FormatFloat(v.Float(), 'f', -1, 64)); !ok {
+			return nil, fmt.Errorf("invalid value for fixed point '%v'", v.Float())
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		r = new(big.Rat).SetInt64(v.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		r = new(big.Rat).SetInt(new(big.Int).SetUint64(v.Uint()))
+
+	default:
+		return nil, encodeErr(v, "fixed point")
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.decimals)), nil)
+	scaled := r.Mul(r, new(big.Rat).SetInt(scale))
+	if !scaled.IsInt() {
+		return nil, fmt.Errorf("value has more than %d decimals for type %s", t.decimals, t.String())
+	}
+	return scaled.Num(), nil
+}
+
+// decodeFixed reads a fixed point value from its scaled integer in big
+// endian, sign extended to the length of the word for signed types
+func decodeFixed(t *Type, word []byte) *big.Rat {
+	n := DecodeMinimalInt(word, t.signed)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(t.decimals)), nil)
+	return new(big.Rat).SetFrac(n, scale)
+}
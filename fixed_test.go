@@ -0,0 +1,105 @@
+package abi
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixedPoint_Type(t *testing.T) {
+	cases := []struct {
+		str      string
+		expected string
+		decimals int
+	}{
+		{"fixed128x18", "fixed128x18", 18},
+		{"ufixed64x2", "ufixed64x2", 2},
+		{"fixed", "fixed128x18", 18},
+		{"ufixed", "ufixed128x18", 18},
+		{"fixed8x0[2]", "fixed8x0[2]", 0},
+	}
+	for _, c := range cases {
+		typ, err := NewType(c.str)
+		require.NoError(t, err, c.str)
+		require.Equal(t, c.expected, typ.String())
+	}
+
+	for _, str := range []string{"fixed7x2", "fixed264x2", "ufixed128x81", "fixed128"} {
+		_, err := NewType(str)
+		require.Error(t, err, str)
+	}
+}
+
+func TestFixedPoint_Encode(t *testing.T) {
+	typ := MustNewType("fixed16x2")
+
+	cases := []struct {
+		input  interface{}
+		packed []byte
+	}{
+		{"1.5", []byte{0x0, 0x96}},
+		{1.5, []byte{0x0, 0x96}},
+		{big.NewRat(-1, 100), []byte{0xff, 0xff}},
+		{int64(2), []byte{0x0, 0xc8}},
+		{"0.1", []byte{0x0, 0x0a}},
+	}
+	for _, c := range cases {
+		res, err := EncodePacked(c.input, typ)
+		require.NoError(t, err)
+		require.Equal(t, c.packed, res)
+
+		val, err := DecodePacked(typ, res)
+		require.NoError(t, err)
+
+		// the standard encoding sign extends the scaled integer to the word
+		std, err := Encode(c.input, typ)
+		require.NoError(t, err)
+		require.Equal(t, c.packed, std[30:])
+
+		stdVal, err := Decode(typ, std)
+		require.NoError(t, err)
+		require.Equal(t, val, stdVal)
+	}
+
+	_, err := EncodePacked("1.555", typ)
+	require.ErrorContains(t, err, "value has more than 2 decimals for type fixed16x2")
+
+	_, err = EncodePackedWithOptions("400", typ, &EncodeOptions{CheckRange: true})
+	require.Error(t, err)
+
+	// the floats that are not a number are rejected on both encodings
+	fixed := MustNewType("fixed128x18")
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err = EncodePacked(f, fixed)
+		require.ErrorContains(t, err, "invalid value for fixed point")
+
+		_, err = Encode(f, fixed)
+		require.ErrorContains(t, err, "invalid value for fixed point")
+	}
+}
+
+func TestFixedPoint_Decode(t *testing.T) {
+	val, err := DecodePacked(MustNewType("fixed16x2"), []byte{0xff, 0x6a})
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(-3, 2), val)
+
+	val, err = DecodePacked(MustNewType("ufixed16x2"), []byte{0xff, 0x6a})
+	require.NoError(t, err)
+	require.Equal(t, big.NewRat(65386, 100), val)
+
+	// array elements are padded to 32 bytes
+	arr := MustNewType("fixed16x1[2]")
+	input, err := EncodePacked([2]string{"-0.5", "1"}, arr)
+	require.NoError(t, err)
+	require.Len(t, input, 64)
+
+	val, err = DecodePacked(arr, input)
+	require.NoError(t, err)
+	require.Equal(t, [2]*big.Rat{big.NewRat(-1, 2), big.NewRat(1, 1)}, val)
+
+	res, err := DecodePackedJSON(MustNewType("tuple(ufixed16x2 price)"), []byte{0x0, 0x96})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"price": "1.50"}`, string(res))
+}
@@ -0,0 +1,117 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/umbracle/ethgo"
+)
+
+// FormatValue returns the canonical string form of a decoded value of
+// type t. Tuples are formatted as (a, b), arrays and slices as [a, b],
+// numbers in decimal, addresses in their checksum form, bytes as hex and
+// strings quoted
+func FormatValue(t *Type, v interface{}) (string, error) {
+	var b strings.Builder
+	if err := formatValue(&b, t, v); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// DecodePackedWithFormat decodes the packed input with a given type and
+// returns the value along with its canonical string form
+func DecodePackedWithFormat(t *Type, input []byte) (interface{}, string, error) {
+	val, err := DecodePacked(t, input)
+	if err != nil {
+		return nil, "", err
+	}
+	str, err := FormatValue(t, val)
+	if err != nil {
+		return nil, "", err
+	}
+	return val, str, nil
+}
+
+func formatValue(b *strings.Builder, t *Type, v interface{}) error {
+	switch t.Kind() {
+	case KindTuple:
+		tuple, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("expected a tuple value but found %T", v)
+		}
+		b.WriteString("(")
+		for indx, elem := range t.TupleElems() {
+			if indx != 0 {
+				b.WriteString(", ")
+			}
+			name := elem.Name
+			if name == "" {
+				name = strconv.Itoa(indx)
+			}
+			if err := formatValue(b, elem.Elem, tuple[name]); err != nil {
+				return err
+			}
+		}
+		b.WriteString(")")
+		return nil
+
+	case KindSlice, KindArray:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return fmt.Errorf("expected a list value but found %T", v)
+		}
+		b.WriteString("[")
+		for i := 0; i < rv.Len(); i++ {
+			if i != 0 {
+				b.WriteString(", ")
+			}
+			if err := formatValue(b, t.Elem(), rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		b.WriteString("]")
+		return nil
+
+	case KindBytes, KindFixedBytes, KindFunction:
+		rv := reflect.ValueOf(v)
+		if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) && rv.Type().Elem().Kind() == reflect.Uint8 {
+			b.WriteString("0x")
+			b.WriteString(hex.EncodeToString(convertArrayToBytes(rv).Bytes()))
+			return nil
+		}
+
+	case KindAddress:
+		if addr, ok := v.(ethgo.Address); ok {
+			b.WriteString(addr.String())
+			return nil
+		}
+
+	case KindString:
+		if str, ok := v.(string); ok {
+			b.WriteString(strconv.Quote(str))
+			return nil
+		}
+
+	case KindInt, KindUInt:
+		if num, ok := v.(*big.Int); ok {
+			b.WriteString(num.String())
+			return nil
+		}
+
+	case KindFixedPoint:
+		if num, ok := v.(*big.Rat); ok {
+			b.WriteString(num.FloatString(t.Decimals()))
+			return nil
+		}
+	}
+
+	// booleans, native integers and the values returned by the
+	// registered converters
+	fmt.Fprint(b, v)
+	return nil
+}
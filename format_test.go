@@ -0,0 +1,47 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestDecodePackedWithFormat(t *testing.T) {
+	typ := MustNewType("tuple(address a, uint256 b, int8 c, bool d, bytes2 e, uint8[2] f, string g)")
+	input := mustDecodeHex("0x" +
+		"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed" +
+		"00000000000000000000000000000000000000000000000000000000000003e8" +
+		"ff" +
+		"01" +
+		"0102" +
+		"0000000000000000000000000000000000000000000000000000000000000003" +
+		"0000000000000000000000000000000000000000000000000000000000000004" +
+		"6869")
+
+	val, str, err := DecodePackedWithFormat(typ, input)
+	require.NoError(t, err)
+	require.Equal(t, `(0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed, 1000, -1, true, 0x0102, [3, 4], "hi")`, str)
+	require.Equal(t, map[string]interface{}{
+		"a": ethgo.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		"b": big.NewInt(1000),
+		"c": int8(-1),
+		"d": true,
+		"e": [2]byte{0x1, 0x2},
+		"f": [2]uint8{3, 4},
+		"g": "hi",
+	}, val)
+
+	_, err = FormatValue(typ, "not a tuple")
+	require.Error(t, err)
+
+	// the fixed point values are formatted with the decimals of the type
+	fixed := MustNewType("tuple(ufixed16x2 a, fixed16x2[2] b)")
+	input, err = EncodePacked(map[string]interface{}{"a": "1.5", "b": [2]string{"-0.25", "3"}}, fixed)
+	require.NoError(t, err)
+
+	_, str, err = DecodePackedWithFormat(fixed, input)
+	require.NoError(t, err)
+	require.Equal(t, "(1.50, [-0.25, 3.00])", str)
+}
@@ -0,0 +1,144 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/umbracle/ethgo"
+)
+
+var (
+	gethAddressT = reflect.TypeOf(common.Address{})
+	gethHashT    = reflect.TypeOf(common.Hash{})
+	gethBytesT   = reflect.TypeOf(hexutil.Bytes{})
+)
+
+// FromGethType converts a go-ethereum abi type into a type of this package.
+// The names of the tuple elements are kept
+func FromGethType(t gethabi.Type) (*Type, error) {
+	switch t.T {
+	case gethabi.TupleTy:
+		elems := make([]*TupleElem, len(t.TupleElems))
+		for i, elem := range t.TupleElems {
+			typ, err := FromGethType(*elem)
+			if err != nil {
+				return nil, err
+			}
+			elems[i] = &TupleElem{Elem: typ}
+			if i < len(t.TupleRawNames) {
+				elems[i].Name = t.TupleRawNames[i]
+			}
+		}
+		return NewTupleType(elems), nil
+
+	case gethabi.SliceTy:
+		elem, err := FromGethType(*t.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return NewSliceType(elem)
+
+	case gethabi.ArrayTy:
+		elem, err := FromGethType(*t.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return NewArrayType(elem, t.Size)
+
+	case gethabi.FixedPointTy:
+		return nil, fmt.Errorf("fixed point type %s not supported", t.String())
+
+	default:
+		return NewType(t.String())
+	}
+}
+
+// FromGethArguments converts a list of go-ethereum abi arguments (i.e. the
+// inputs of a method or an event) into a tuple type
+func FromGethArguments(args gethabi.Arguments) (*Type, error) {
+	elems := make([]*TupleElem, len(args))
+	for i, arg := range args {
+		typ, err := FromGethType(arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert argument '%s': %v", arg.Name, err)
+		}
+		elems[i] = &TupleElem{
+			Name:    arg.Name,
+			Elem:    typ,
+			Indexed: arg.Indexed,
+		}
+	}
+	return NewTupleType(elems), nil
+}
+
+// encodeGethFast encodes the go-ethereum address, hash and bytes types
+// without reflection. It returns false if the value is not supported
+func encodeGethFast(v interface{}, t *Type) ([]byte, bool) {
+	switch obj := v.(type) {
+	case common.Address:
+		if t.Kind() == KindAddress {
+			return append([]byte{}, obj[:]...), true
+		}
+
+	case common.Hash:
+		if t.Kind() == KindFixedBytes && t.Size() == 32 {
+			return append([]byte{}, obj[:]...), true
+		}
+
+	case hexutil.Bytes:
+		if t.Kind() == KindBytes {
+			return append([]byte{}, obj...), true
+		}
+	}
+	return nil, false
+}
+
+// gethHook converts the decoded addresses, hashes and bytes into the
+// go-ethereum types
+func gethHook(to reflect.Type, data interface{}) interface{} {
+	switch to {
+	case gethAddressT:
+		if addr, ok := data.(ethgo.Address); ok {
+			return common.Address(addr)
+		}
+
+	case gethHashT:
+		if hash, ok := data.([32]byte); ok {
+			return common.Hash(hash)
+		}
+
+	case gethBytesT:
+		if buf, ok := data.([]byte); ok {
+			return hexutil.Bytes(buf)
+		}
+	}
+	return data
+}
+
+// decodeGethFast assigns the decoded addresses, hashes and bytes to out
+// params of the go-ethereum types without reflection
+func decodeGethFast(val interface{}, out interface{}) bool {
+	switch dst := out.(type) {
+	case *common.Address:
+		if v, ok := val.(ethgo.Address); ok {
+			*dst = common.Address(v)
+			return true
+		}
+
+	case *common.Hash:
+		if v, ok := val.([32]byte); ok {
+			*dst = v
+			return true
+		}
+
+	case *hexutil.Bytes:
+		if v, ok := val.([]byte); ok {
+			*dst = append(hexutil.Bytes{}, v...)
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package abi
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	gethabi "github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+const gethTestABI = `[
+	{
+		"type": "function",
+		"name": "transfer",
+		"inputs": [
+			{"name": "to", "type": "address"},
+			{"name": "order", "type": "tuple", "components": [
+				{"name": "id", "type": "bytes32"},
+				{"name": "amounts", "type": "uint256[2]"}
+			]},
+			{"name": "data", "type": "bytes"}
+		]
+	}
+]`
+
+func TestFromGethArguments(t *testing.T) {
+	parsed, err := gethabi.JSON(strings.NewReader(gethTestABI))
+	require.NoError(t, err)
+
+	method := parsed.Methods["transfer"]
+	typ, err := FromGethArguments(method.Inputs)
+	require.NoError(t, err)
+	require.Equal(t, "tuple(address to,tuple(bytes32 id,uint256[2] amounts) order,bytes data)", typ.Format(true))
+
+	type Order struct {
+		Id      common.Hash
+		Amounts [2]*big.Int
+	}
+	order := Order{
+		Id:      common.Hash{0x1},
+		Amounts: [2]*big.Int{big.NewInt(1), big.NewInt(2)},
+	}
+	to := common.Address{0x2}
+	data := hexutil.Bytes{0x3, 0x4}
+
+	// the standard encoding matches the one of go-ethereum
+	expected, err := method.Inputs.Pack(to, order, []byte(data))
+	require.NoError(t, err)
+
+	res, err := Encode(map[string]interface{}{"to": to, "order": order, "data": data}, typ)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	// and it decodes into the go-ethereum types
+	type Transfer struct {
+		To    common.Address
+		Order Order
+		Data  hexutil.Bytes
+	}
+	out, err := DecodeAs[Transfer](typ, res)
+	require.NoError(t, err)
+	require.Equal(t, Transfer{To: to, Order: order, Data: data}, out)
+}
+
+func TestGethTypes_Packed(t *testing.T) {
+	addr := common.Address{0x1}
+	hash := common.Hash{0x2}
+
+	res, err := EncodePacked(addr, MustNewType("address"))
+	require.NoError(t, err)
+	require.Equal(t, addr.Bytes(), res)
+
+	res, err = EncodePacked(hash, MustNewType("bytes32"))
+	require.NoError(t, err)
+	require.Equal(t, hash.Bytes(), res)
+
+	in := hexutil.Bytes{0x3}
+	res, err = EncodePacked(in, MustNewType("bytes"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x3}, res)
+
+	// the result does not share memory with the input
+	res[0] = 0x9
+	require.Equal(t, hexutil.Bytes{0x3}, in)
+
+	var outAddr common.Address
+	require.NoError(t, DecodePackedInto(MustNewType("address"), addr.Bytes(), &outAddr))
+	require.Equal(t, addr, outAddr)
+
+	var outHash common.Hash
+	require.NoError(t, DecodePackedInto(MustNewType("bytes32"), hash.Bytes(), &outHash))
+	require.Equal(t, hash, outHash)
+
+	input := []byte{0x3}
+	var outBytes hexutil.Bytes
+	require.NoError(t, DecodePackedInto(MustNewType("bytes"), input, &outBytes))
+	require.Equal(t, hexutil.Bytes{0x3}, outBytes)
+
+	outBytes[0] = 0x9
+	require.Equal(t, []byte{0x3}, input)
+}
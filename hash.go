@@ -0,0 +1,63 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+	"hash"
+)
+
+// HashPacked returns the keccak256 hash of the packed encoding of the
+// values, the equivalent of keccak256(abi.encodePacked(...)) in solidity
+func HashPacked(types []*Type, values []interface{}) ([32]byte, error) {
+	var res [32]byte
+
+	data, err := EncodePackedArgs(types, values)
+	if err != nil {
+		return res, err
+	}
+	copy(res[:], keccak256(data))
+	return res, nil
+}
+
+// EncodePackedAndHash returns the keccak256 hash of the packed encoding
+// of the values, like soliditySha3 in web3
+func EncodePackedAndHash(values []interface{}, types []*Type) ([32]byte, error) {
+	return HashPacked(types, values)
+}
+
+// EncodePackedAndHashWith returns the hash of the packed encoding of the
+// values with the given hasher (i.e. sha256.New()). The hasher must
+// return 32 bytes
+func EncodePackedAndHashWith(values []interface{}, types []*Type, h hash.Hash) ([32]byte, error) {
+	var res [32]byte
+	if h.Size() != len(res) {
+		return res, fmt.Errorf("hasher size must be %d bytes but found %d", len(res), h.Size())
+	}
+
+	data, err := EncodePackedArgs(types, values)
+	if err != nil {
+		return res, err
+	}
+	h.Reset()
+	h.Write(data)
+	copy(res[:], h.Sum(nil))
+	return res, nil
+}
+
+// LeafHash returns the hash of a merkle tree leaf built from the packed
+// encoding of the values (i.e. an address and an amount in allowlists)
+func LeafHash(types []*Type, values []interface{}) ([32]byte, error) {
+	return HashPacked(types, values)
+}
+
+// HashPair returns the hash of two merkle tree nodes. The nodes are sorted
+// before hashing like in the OpenZeppelin MerkleProof library, so the
+// result does not depend on the position of the nodes
+func HashPair(a, b [32]byte) [32]byte {
+	var res [32]byte
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	copy(res[:], keccak256(a[:], b[:]))
+	return res
+}
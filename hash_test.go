@@ -0,0 +1,54 @@
+package abi
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestLeafHash(t *testing.T) {
+	types := []*Type{MustNewType("address"), MustNewType("uint256")}
+
+	leaf1, err := LeafHash(types, []interface{}{
+		ethgo.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		big.NewInt(100),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0xc8224a494982e4eafa93ad0c81c9945d5489718a092793c1d803ac7493126774", encodeHex(leaf1[:]))
+
+	leaf2, err := LeafHash(types, []interface{}{
+		ethgo.HexToAddress("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"),
+		big.NewInt(256),
+	})
+	require.NoError(t, err)
+	require.Equal(t, "0xe3cd6df477e48f00b227af283e6a18345b5d49f8764916aa26cd6bc12b8479ef", encodeHex(leaf2[:]))
+
+	// the pair is sorted before hashing
+	parent := HashPair(leaf1, leaf2)
+	require.Equal(t, "0x3dc719a3ccac4ec34faa5b8209e5ac649334e8b0315e46fa93cb51a0cbb29069", encodeHex(parent[:]))
+	require.Equal(t, parent, HashPair(leaf2, leaf1))
+
+	_, err = HashPacked(types, []interface{}{ethgo.Address{}})
+	require.Error(t, err)
+}
+
+func TestEncodePackedAndHash(t *testing.T) {
+	types := []*Type{MustNewType("string"), MustNewType("uint8")}
+	values := []interface{}{"hello", uint8(1)}
+
+	res, err := EncodePackedAndHash(values, types)
+	require.NoError(t, err)
+	require.Equal(t, keccak256([]byte("hello"), []byte{0x1}), res[:])
+
+	// pluggable hasher
+	res, err = EncodePackedAndHashWith(values, types, sha256.New())
+	require.NoError(t, err)
+	require.Equal(t, sha256.Sum256([]byte("hello\x01")), res)
+
+	_, err = EncodePackedAndHashWith(values, types, sha512.New())
+	require.EqualError(t, err, "hasher size must be 32 bytes but found 64")
+}
@@ -0,0 +1,267 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+
+	"github.com/umbracle/ethgo"
+)
+
+// DecodePackedJSON decodes the packed input with a given type and renders
+// the result as json. Bytes, fixed bytes and functions are rendered as hex
+// strings, strings as json strings, addresses in their checksum form and
+// big integers as decimal strings to avoid losing precision
+func DecodePackedJSON(t *Type, input []byte) ([]byte, error) {
+	val, err := DecodePacked(t, input)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := jsonValue(t, val)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// EncodeFromJSON encodes a json value with a given type in the standard
+// encoding. Tuples are json objects with the names of the elements or
+// json arrays in order, numbers are json numbers or decimal and hex
+// strings, and bytes are hex strings
+func EncodeFromJSON(t *Type, raw json.RawMessage) ([]byte, error) {
+	val, err := valueFromJSON(t, raw)
+	if err != nil {
+		return nil, err
+	}
+	return Encode(val, t)
+}
+
+// EncodePackedFromJSON encodes a json value with a given type in packed
+// mode. The json values are mapped like in EncodeFromJSON
+func EncodePackedFromJSON(t *Type, raw json.RawMessage) ([]byte, error) {
+	val, err := valueFromJSON(t, raw)
+	if err != nil {
+		return nil, err
+	}
+	return EncodePacked(val, t)
+}
+
+// valueFromJSON parses the raw json and converts it into a value that
+// the encoders accept for the type t
+func valueFromJSON(t *Type, raw json.RawMessage) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+
+	var obj interface{}
+	if err := dec.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to parse json: %v", err)
+	}
+	return convertJSON(t, obj)
+}
+
+var interfaceT = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// convertJSON converts a parsed json value into a value of type t
+func convertJSON(t *Type, obj interface{}) (interface{}, error) {
+	switch t.Kind() {
+	case KindTuple:
+		var err error
+		res := map[string]interface{}{}
+		switch v := obj.(type) {
+		case map[string]interface{}:
+			for indx, elem := range t.TupleElems() {
+				name := elem.Name
+				if name == "" {
+					name = strconv.Itoa(indx)
+				}
+				val, ok := v[name]
+				if !ok {
+					return nil, fmt.Errorf("field '%s' not found", name)
+				}
+				if res[name], err = convertJSON(elem.Elem, val); err != nil {
+					return nil, err
+				}
+			}
+
+		case []interface{}:
+			if len(v) != len(t.TupleElems()) {
+				return nil, fmt.Errorf("expected %d tuple values but found %d", len(t.TupleElems()), len(v))
+			}
+			for indx, elem := range t.TupleElems() {
+				name := elem.Name
+				if name == "" {
+					name = strconv.Itoa(indx)
+				}
+				if res[name], err = convertJSON(elem.Elem, v[indx]); err != nil {
+					return nil, err
+				}
+			}
+
+		default:
+			return nil, fmt.Errorf("expected a json object or array for %s", t.String())
+		}
+		return res, nil
+
+	case KindSlice, KindArray:
+		list, ok := obj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a json array for %s", t.String())
+		}
+		if t.Kind() == KindArray && len(list) != t.Size() {
+			return nil, fmt.Errorf("expected %d array values but found %d", t.Size(), len(list))
+		}
+
+		var res reflect.Value
+		if t.Kind() == KindArray {
+			res = reflect.New(reflect.ArrayOf(len(list), interfaceT)).Elem()
+		} else {
+			res = reflect.MakeSlice(reflect.SliceOf(interfaceT), len(list), len(list))
+		}
+		for i, elem := range list {
+			val, err := convertJSON(t.Elem(), elem)
+			if err != nil {
+				return nil, err
+			}
+			res.Index(i).Set(reflect.ValueOf(&val).Elem())
+		}
+		return res.Interface(), nil
+
+	case KindInt, KindUInt:
+		switch v := obj.(type) {
+		case json.Number:
+			return v.String(), nil
+		case string:
+			return v, nil
+		}
+		return nil, fmt.Errorf("expected a json number or string for %s", t.String())
+
+	case KindFixedPoint:
+		switch v := obj.(type) {
+		case json.Number:
+			r, ok := new(big.Rat).SetString(v.String())
+			if !ok {
+				return nil, fmt.Errorf("invalid fixed point value '%s'", v.String())
+			}
+			return r, nil
+		case string:
+			return v, nil
+		}
+		return nil, fmt.Errorf("expected a json number or string for %s", t.String())
+
+	case KindBool:
+		if v, ok := obj.(bool); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("expected a json boolean for %s", t.String())
+
+	default:
+		// strings, addresses and the bytes types are strings
+		if v, ok := obj.(string); ok {
+			return v, nil
+		}
+		return nil, fmt.Errorf("expected a json string for %s", t.String())
+	}
+}
+
+// TypedValue is a decoded value with its type. It marshals into json like
+// DecodePackedJSON so it can be embedded in the responses of http APIs
+type TypedValue struct {
+	Type  *Type
+	Value interface{}
+}
+
+// DecodePackedTypedValue decodes the packed input with a given type and
+// returns the value together with its type
+func DecodePackedTypedValue(t *Type, input []byte) (*TypedValue, error) {
+	val, err := DecodePacked(t, input)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedValue{Type: t, Value: val}, nil
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (v *TypedValue) MarshalJSON() ([]byte, error) {
+	obj, err := jsonValue(v.Type, v.Value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// jsonValue converts a decoded value of type t into a value that
+// marshals into the expected json representation
+func jsonValue(t *Type, v interface{}) (interface{}, error) {
+	switch t.Kind() {
+	case KindTuple:
+		tuple, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a tuple value but found %T", v)
+		}
+		res := make(map[string]interface{}, len(tuple))
+		for indx, elem := range t.TupleElems() {
+			name := elem.Name
+			if name == "" {
+				name = strconv.Itoa(indx)
+			}
+			val, err := jsonValue(elem.Elem, tuple[name])
+			if err != nil {
+				return nil, err
+			}
+			res[name] = val
+		}
+		return res, nil
+
+	case KindSlice, KindArray:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return nil, fmt.Errorf("expected a list value but found %T", v)
+		}
+		res := make([]interface{}, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			val, err := jsonValue(t.Elem(), rv.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			res[i] = val
+		}
+		return res, nil
+
+	case KindBytes, KindFixedBytes, KindFunction:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return "0x" + hex.EncodeToString(rv.Bytes()), nil
+		}
+		if rv.Kind() == reflect.Array && rv.Type().Elem().Kind() == reflect.Uint8 {
+			buf := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(buf), rv)
+			return "0x" + hex.EncodeToString(buf), nil
+		}
+
+	case KindAddress:
+		if addr, ok := v.(ethgo.Address); ok {
+			return addr.String(), nil
+		}
+		if addr, ok := v.([20]byte); ok {
+			return ethgo.Address(addr).String(), nil
+		}
+
+	case KindInt, KindUInt:
+		if num, ok := v.(*big.Int); ok {
+			return num.String(), nil
+		}
+
+	case KindFixedPoint:
+		if num, ok := v.(*big.Rat); ok {
+			return num.FloatString(t.Decimals()), nil
+		}
+	}
+
+	// strings, booleans, native integers and the values returned by the
+	// registered converters are marshaled as they are
+	return v, nil
+}
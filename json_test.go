@@ -0,0 +1,147 @@
+package abi
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestDecodePackedJSON(t *testing.T) {
+	typ := MustNewType("tuple(address a, uint256 b, uint8 c, bool d, bytes3 e, string f)")
+	input := mustDecodeHex("0x" +
+		"5aaeb6053f3e94c9b9a09f33669435e7ef1beaed" +
+		"00000000000000000000000000000000000000000000000000000000000003e8" +
+		"07" +
+		"01" +
+		"616263" +
+		"616263")
+
+	res, err := DecodePackedJSON(typ, input)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"a": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"b": "1000",
+		"c": 7,
+		"d": true,
+		"e": "0x616263",
+		"f": "abc"
+	}`, string(res))
+}
+
+func TestDecodePackedJSON_BytesAndString(t *testing.T) {
+	input := []byte("abc")
+
+	res, err := DecodePackedJSON(MustNewType("bytes"), input)
+	require.NoError(t, err)
+	require.Equal(t, `"0x616263"`, string(res))
+
+	res, err = DecodePackedJSON(MustNewType("string"), input)
+	require.NoError(t, err)
+	require.Equal(t, `"abc"`, string(res))
+
+	input = append(rightPad([]byte{0x61}, 32), rightPad([]byte{0x62}, 32)...)
+	input = append(input, rightPad([]byte{0x63}, 32)...)
+
+	res, err = DecodePackedJSON(MustNewType("bytes1[3]"), input)
+	require.NoError(t, err)
+	require.Equal(t, `["0x61","0x62","0x63"]`, string(res))
+}
+
+func TestTypedValue_MarshalJSON(t *testing.T) {
+	typ := MustNewType("tuple(address owner, uint256 balance, bytes2 tag)")
+	input, err := EncodePacked(map[string]interface{}{
+		"owner":   ethgo.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		"balance": new(big.Int).Lsh(big.NewInt(1), 100),
+		"tag":     [2]byte{0xab, 0xcd},
+	}, typ)
+	require.NoError(t, err)
+
+	val, err := DecodePackedTypedValue(typ, input)
+	require.NoError(t, err)
+
+	// the value can be embedded in other json objects
+	res, err := json.Marshal(map[string]interface{}{"result": val})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"result": {
+		"owner": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"balance": "1267650600228229401496703205376",
+		"tag": "0xabcd"
+	}}`, string(res))
+
+	// values from the standard decoding are supported too
+	std, err := Decode(MustNewType("tuple(uint256 a)"), leftPad([]byte{0x7}, 32))
+	require.NoError(t, err)
+
+	res, err = json.Marshal(&TypedValue{Type: MustNewType("tuple(uint256 a)"), Value: std})
+	require.NoError(t, err)
+	require.Equal(t, `{"a":"7"}`, string(res))
+}
+
+func TestEncodeFromJSON(t *testing.T) {
+	typ := MustNewType("tuple(address to, uint256 amount, bool ok, bytes data, uint8[2] ids, tuple(string name, int8 delta)[] items)")
+	raw := json.RawMessage(`{
+		"to": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"amount": 1000000000000000000000,
+		"ok": true,
+		"data": "0x0102",
+		"ids": [1, "0x2"],
+		"items": [{"name": "a", "delta": -1}, ["b", 2]]
+	}`)
+
+	expected := map[string]interface{}{
+		"to":     ethgo.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"),
+		"amount": new(big.Int).Mul(big.NewInt(1000), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)),
+		"ok":     true,
+		"data":   []byte{0x1, 0x2},
+		"ids":    [2]uint8{1, 2},
+		"items": []map[string]interface{}{
+			{"name": "a", "delta": int8(-1)},
+			{"name": "b", "delta": int8(2)},
+		},
+	}
+
+	res, err := EncodeFromJSON(typ, raw)
+	require.NoError(t, err)
+
+	std, err := Encode(expected, typ)
+	require.NoError(t, err)
+	require.Equal(t, std, res)
+
+	res, err = EncodePackedFromJSON(MustNewType("tuple(address to, uint256 amount, uint8[2] ids)"), raw)
+	require.NoError(t, err)
+
+	packed, err := EncodePacked(expected, MustNewType("tuple(address to, uint256 amount, uint8[2] ids)"))
+	require.NoError(t, err)
+	require.Equal(t, packed, res)
+
+	// the fixed point values are json numbers or decimal strings
+	fixed := MustNewType("tuple(ufixed16x2 price, fixed16x2 delta)")
+	res, err = EncodePackedFromJSON(fixed, json.RawMessage(`{"price": 1.5, "delta": "-0.25"}`))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x0, 0x96, 0xff, 0xe7}, res)
+
+	_, err = EncodeFromJSON(fixed, json.RawMessage(`{"price": 1.555, "delta": 0}`))
+	require.ErrorContains(t, err, "value has more than 2 decimals for type ufixed16x2")
+
+	cases := []struct {
+		typ string
+		raw string
+		err string
+	}{
+		{"tuple(uint8 a)", `{"b": 1}`, "field 'a' not found"},
+		{"tuple(uint8 a)", `1`, "expected a json object or array for tuple(uint8)"},
+		{"uint8[2]", `[1]`, "expected 2 array values but found 1"},
+		{"bool", `"true"`, "expected a json boolean for bool"},
+		{"uint8", `true`, "expected a json number or string for uint8"},
+		{"address", `1`, "expected a json string for address"},
+		{"ufixed16x2", `true`, "expected a json number or string for ufixed16x2"},
+		{"uint8", `{`, "failed to parse json: unexpected EOF"},
+	}
+	for _, c := range cases {
+		_, err := EncodeFromJSON(MustNewType(c.typ), json.RawMessage(c.raw))
+		require.EqualError(t, err, c.err, c.typ)
+	}
+}
@@ -0,0 +1,153 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// registry holds the package level registrations: the decode converters,
+// the type hooks, the enums and the user defined value types. Writers
+// publish an updated copy of it, so the lookups done while encoding and
+// decoding values do not take any lock
+type registry struct {
+	converters map[Kind]DecodeConverter
+	hooks      map[reflect.Type]*TypeHook
+	enums      map[string][]string
+	userTypes  map[string]string
+}
+
+var (
+	registryLock    sync.Mutex
+	currentRegistry atomic.Value
+)
+
+// loadRegistry returns the current registry, which must not be modified
+func loadRegistry() *registry {
+	if r, ok := currentRegistry.Load().(*registry); ok {
+		return r
+	}
+	return &registry{}
+}
+
+// updateRegistry applies fn to a copy of the current registry and
+// publishes the copy. The writers are serialized
+func updateRegistry(fn func(r *registry)) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	r := loadRegistry()
+	res := &registry{
+		converters: copyMap(r.converters),
+		hooks:      copyMap(r.hooks),
+		enums:      copyMap(r.enums),
+		userTypes:  copyMap(r.userTypes),
+	}
+	fn(res)
+	currentRegistry.Store(res)
+}
+
+func copyMap[K comparable, V any](m map[K]V) map[K]V {
+	res := make(map[K]V, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
+
+// DecodeConverter builds the decoded value of a type from its packed bytes
+type DecodeConverter func([]byte) (interface{}, error)
+
+// RegisterDecodeConverter registers a function used by the packed decoder
+// to build the values of the given elementary kind instead of the default
+// go type. Registering a nil function removes the converter
+func RegisterDecodeConverter(kind Kind, fn func([]byte) (interface{}, error)) {
+	updateRegistry(func(r *registry) {
+		if fn == nil {
+			delete(r.converters, kind)
+			return
+		}
+		r.converters[kind] = fn
+	})
+}
+
+func getDecodeConverter(kind Kind) (DecodeConverter, bool) {
+	fn, ok := loadRegistry().converters[kind]
+	return fn, ok
+}
+
+// TypeHook maps a custom go type to an abi kind. Encode converts the
+// values of the go type into values the encoder supports (i.e. a custom
+// address type into an ethgo.Address) and Decode converts the decoded
+// values into the go type when decoding into structs. Either function
+// can be nil if the go type is only encoded or only decoded
+type TypeHook struct {
+	Kind   Kind
+	Encode func(v interface{}) (interface{}, error)
+	Decode func(v interface{}) (interface{}, error)
+}
+
+// RegisterTypeHook registers the hook of a go type, which is used by both
+// the standard and the packed encodings. Registering a nil hook removes it
+func RegisterTypeHook(typ reflect.Type, hook *TypeHook) {
+	updateRegistry(func(r *registry) {
+		if hook == nil {
+			delete(r.hooks, typ)
+			return
+		}
+		r.hooks[typ] = hook
+	})
+}
+
+func getTypeHook(typ reflect.Type) (*TypeHook, bool) {
+	hooks := loadRegistry().hooks
+	if len(hooks) == 0 {
+		return nil, false
+	}
+	hook, ok := hooks[typ]
+	return hook, ok
+}
+
+// applyEncodeHook converts the value with the hook of its go type if
+// there is one registered
+func applyEncodeHook(v reflect.Value, t *Type) (reflect.Value, error) {
+	if !v.IsValid() {
+		return v, nil
+	}
+	hook, ok := getTypeHook(v.Type())
+	if !ok {
+		return v, nil
+	}
+	if hook.Kind != t.Kind() {
+		return v, fmt.Errorf("go type %s is registered as %s but found %s", v.Type(), hook.Kind, t.Kind())
+	}
+	if hook.Encode == nil {
+		return v, fmt.Errorf("go type %s does not have an encode hook", v.Type())
+	}
+	val, err := hook.Encode(v.Interface())
+	if err != nil {
+		return v, err
+	}
+	return reflect.ValueOf(val), nil
+}
+
+// typeHookDecode converts the decoded data into the go type with its
+// hook if there is one registered
+func typeHookDecode(to reflect.Type, data interface{}) (interface{}, error) {
+	hook, ok := getTypeHook(to)
+	if !ok || hook.Decode == nil {
+		return data, nil
+	}
+	return hook.Decode(data)
+}
+
+// hasDecodeConverter returns whether the values of the type (or of its
+// array elements) are built with a registered converter
+func hasDecodeConverter(t *Type) bool {
+	for t.Kind() == KindArray || t.Kind() == KindSlice {
+		t = t.Elem()
+	}
+	_, ok := getDecodeConverter(t.Kind())
+	return ok
+}
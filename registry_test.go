@@ -0,0 +1,134 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+type customAddress struct {
+	Hex string
+}
+
+func TestRegisterDecodeConverter(t *testing.T) {
+	RegisterDecodeConverter(KindAddress, func(b []byte) (interface{}, error) {
+		if len(b) != 20 {
+			return nil, fmt.Errorf("bad address length %d", len(b))
+		}
+		return customAddress{Hex: encodeHex(b)}, nil
+	})
+	defer RegisterDecodeConverter(KindAddress, nil)
+
+	addr := ethgo.Address{0x1}
+
+	typ := MustNewType("tuple(address a, uint8 b)")
+	input, err := EncodePacked(map[string]interface{}{"a": addr, "b": uint8(1)}, typ)
+	require.NoError(t, err)
+
+	res, err := DecodePacked(typ, input)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"a": customAddress{Hex: encodeHex(addr[:])},
+		"b": uint8(1),
+	}, res)
+
+	// arrays of converted values are returned as a list of interfaces
+	input, err = EncodePacked([2]ethgo.Address{addr, addr}, MustNewType("address[2]"))
+	require.NoError(t, err)
+
+	res, err = DecodePacked(MustNewType("address[2]"), input)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{
+		customAddress{Hex: encodeHex(addr[:])},
+		customAddress{Hex: encodeHex(addr[:])},
+	}, res)
+}
+
+type hookTimestamp struct {
+	Unix uint64
+}
+
+func TestRegisterTypeHook(t *testing.T) {
+	tsT := reflect.TypeOf(hookTimestamp{})
+	RegisterTypeHook(tsT, &TypeHook{
+		Kind: KindUInt,
+		Encode: func(v interface{}) (interface{}, error) {
+			return v.(hookTimestamp).Unix, nil
+		},
+		Decode: func(v interface{}) (interface{}, error) {
+			switch n := v.(type) {
+			case uint64:
+				return hookTimestamp{Unix: n}, nil
+			case *big.Int:
+				return hookTimestamp{Unix: n.Uint64()}, nil
+			}
+			return nil, fmt.Errorf("unexpected value %T", v)
+		},
+	})
+	defer RegisterTypeHook(tsT, nil)
+
+	type Event struct {
+		Addr ethgo.Address
+		Time hookTimestamp
+	}
+	typ := MustNewType("tuple(address addr, uint64 time)")
+	value := Event{Addr: ethgo.Address{0x1}, Time: hookTimestamp{Unix: 100}}
+
+	// packed encoding
+	input, err := EncodePackedAs(value, typ)
+	require.NoError(t, err)
+	require.Equal(t, append(ethgo.Address{0x1}.Bytes(), 0, 0, 0, 0, 0, 0, 0, 100), input)
+
+	res, err := DecodePackedAs[Event](typ, input)
+	require.NoError(t, err)
+	require.Equal(t, value, res)
+
+	// standard encoding
+	input, err = EncodeAs(value, MustNewType("tuple(address addr, uint256 time)"))
+	require.NoError(t, err)
+
+	res, err = DecodeAs[Event](MustNewType("tuple(address addr, uint256 time)"), input)
+	require.NoError(t, err)
+	require.Equal(t, value, res)
+
+	// the hook does not apply to other kinds
+	_, err = EncodePacked(hookTimestamp{Unix: 1}, MustNewType("address"))
+	require.ErrorContains(t, err, "is registered as Uint but found Address")
+}
+
+func TestRegisterTypeHook_FastPath(t *testing.T) {
+	// the hook applies to the types of the packed fast path
+	RegisterTypeHook(reflect.TypeOf(uint64(0)), &TypeHook{
+		Kind: KindUInt,
+		Encode: func(v interface{}) (interface{}, error) {
+			return v.(uint64) * 2, nil
+		},
+	})
+	defer RegisterTypeHook(reflect.TypeOf(uint64(0)), nil)
+
+	typ := MustNewType("uint8")
+
+	res, err := EncodePacked(uint64(5), typ)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xa}, res)
+
+	res, err = EncodePacked([]interface{}{uint64(5)}, MustNewType("tuple(uint8)"))
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xa}, res)
+
+	res, err = EncodePacked([]uint64{5}, MustNewType("uint64[]"))
+	require.NoError(t, err)
+	require.Equal(t, byte(0xa), res[31])
+
+	res, err = AppendPacked(nil, uint64(5), typ)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0xa}, res)
+
+	res, err = Encode(uint64(5), typ)
+	require.NoError(t, err)
+	require.Equal(t, byte(0xa), res[31])
+}
@@ -0,0 +1,179 @@
+package abi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// SignatureResolver looks up the text signatures of a method selector
+// (i.e. 'transfer(address,uint256)' for 0xa9059cbb). Different methods
+// can have the same selector so it may return more than one signature
+type SignatureResolver interface {
+	ResolveSelector(ctx context.Context, selector [4]byte) ([]string, error)
+}
+
+const (
+	// OpenchainURL is the lookup endpoint of the openchain signature database
+	OpenchainURL = "https://api.openchain.xyz/signature-database/v1/lookup"
+
+	// FourByteURL is the signatures endpoint of the 4byte directory
+	FourByteURL = "https://www.4byte.directory/api/v1/signatures/"
+)
+
+// maxResolverResponse is the largest response read from a signature database
+const maxResolverResponse = 1 << 20
+
+// HTTPResolver resolves the selectors with an http signature database
+type HTTPResolver struct {
+	// URL is the endpoint of the database
+	URL string
+
+	// Client is the http client of the requests, a client with a
+	// 10 seconds timeout by default
+	Client *http.Client
+
+	query func(selector string) url.Values
+	parse func(selector string, resp []byte) ([]string, error)
+}
+
+// NewOpenchainResolver creates a resolver for the openchain api. An
+// empty url uses OpenchainURL
+func NewOpenchainResolver(endpoint string) *HTTPResolver {
+	if endpoint == "" {
+		endpoint = OpenchainURL
+	}
+	return &HTTPResolver{
+		URL: endpoint,
+		query: func(selector string) url.Values {
+			return url.Values{"function": {selector}, "filter": {"true"}}
+		},
+		parse: parseOpenchain,
+	}
+}
+
+// NewFourByteResolver creates a resolver for the 4byte directory api. An
+// empty url uses FourByteURL
+func NewFourByteResolver(endpoint string) *HTTPResolver {
+	if endpoint == "" {
+		endpoint = FourByteURL
+	}
+	return &HTTPResolver{
+		URL: endpoint,
+		query: func(selector string) url.Values {
+			return url.Values{"hex_signature": {selector}}
+		},
+		parse: parseFourByte,
+	}
+}
+
+// ResolveSelector implements the SignatureResolver interface
+func (r *HTTPResolver) ResolveSelector(ctx context.Context, selector [4]byte) ([]string, error) {
+	client := r.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	id := encodeHex(selector[:])
+
+	// the query of the endpoint is kept
+	endpoint, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolver url '%s': %v", r.URL, err)
+	}
+	query := endpoint.Query()
+	for k, v := range r.query(id) {
+		query[k] = v
+	}
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to resolve selector %s: status %d", id, resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResolverResponse+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of selector %s: %v", id, err)
+	}
+	if len(body) > maxResolverResponse {
+		return nil, fmt.Errorf("response of selector %s is larger than %d bytes", id, maxResolverResponse)
+	}
+	return r.parse(id, body)
+}
+
+func parseOpenchain(selector string, resp []byte) ([]string, error) {
+	var res struct {
+		Ok     bool `json:"ok"`
+		Result struct {
+			Function map[string][]struct {
+				Name string `json:"name"`
+			} `json:"function"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(resp, &res); err != nil {
+		return nil, err
+	}
+	if !res.Ok {
+		return nil, fmt.Errorf("failed to resolve selector %s", selector)
+	}
+	sigs := []string{}
+	for _, entry := range res.Result.Function[selector] {
+		sigs = append(sigs, entry.Name)
+	}
+	return sigs, nil
+}
+
+func parseFourByte(selector string, resp []byte) ([]string, error) {
+	var res struct {
+		Results []struct {
+			TextSignature string `json:"text_signature"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(resp, &res); err != nil {
+		return nil, err
+	}
+	sigs := []string{}
+	for _, entry := range res.Results {
+		sigs = append(sigs, entry.TextSignature)
+	}
+	return sigs, nil
+}
+
+// resolveCalldata decodes the calldata with the first signature of the
+// resolver that matches the selector and the arguments, that is, the
+// arguments are encoded back to the same calldata. Unrelated signatures
+// with the same selector can decode a prefix of the calldata. The
+// arguments are named with their positions
+func resolveCalldata(ctx context.Context, r SignatureResolver, selector [4]byte, input []byte) (string, map[string]interface{}, error) {
+	sigs, err := r.ResolveSelector(ctx, selector)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, sig := range sigs {
+		m, err := NewMethod(sig)
+		if err != nil || MethodID(m.Sig()) != selector {
+			continue
+		}
+		args, err := m.DecodeInputs(input)
+		if err != nil {
+			continue
+		}
+		if data, err := m.Encode(args); err == nil && bytes.Equal(data, input) {
+			return m.Name, args, nil
+		}
+	}
+	return "", nil, fmt.Errorf("no method found for selector 0x%x", selector)
+}
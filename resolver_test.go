@@ -0,0 +1,99 @@
+package abi
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestHTTPResolver_Openchain(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		fmt.Fprint(w, `{"ok":true,"result":{"event":{},"function":{"0xa9059cbb":[{"name":"transfer(address,uint256)","filtered":false}]}}}`)
+	}))
+	defer srv.Close()
+
+	// the query of the endpoint is kept
+	sigs, err := NewOpenchainResolver(srv.URL+"?key=abc").ResolveSelector(context.Background(), MethodID("transfer(address,uint256)"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"transfer(address,uint256)"}, sigs)
+	query := <-queries
+	require.Equal(t, "0xa9059cbb", query.Get("function"))
+	require.Equal(t, "abc", query.Get("key"))
+}
+
+func TestHTTPResolver_FourByte(t *testing.T) {
+	queries := make(chan url.Values, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries <- r.URL.Query()
+		fmt.Fprint(w, `{"count":2,"results":[{"id":2,"text_signature":"many_msg_babbage(bytes1)","hex_signature":"0xa9059cbb"},{"id":1,"text_signature":"transfer(address,uint256)","hex_signature":"0xa9059cbb"}]}`)
+	}))
+	defer srv.Close()
+
+	sigs, err := NewFourByteResolver(srv.URL).ResolveSelector(context.Background(), MethodID("transfer(address,uint256)"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"many_msg_babbage(bytes1)", "transfer(address,uint256)"}, sigs)
+	query := <-queries
+	require.Equal(t, "0xa9059cbb", query.Get("hex_signature"))
+}
+
+func TestHTTPResolver_Status(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	_, err := NewOpenchainResolver(srv.URL).ResolveSelector(context.Background(), [4]byte{0x1, 0x2, 0x3, 0x4})
+	require.EqualError(t, err, "failed to resolve selector 0x01020304: status 500")
+}
+
+func TestHTTPResolver_Limits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, maxResolverResponse+1))
+	}))
+	defer srv.Close()
+
+	resolver := NewFourByteResolver(srv.URL)
+	_, err := resolver.ResolveSelector(context.Background(), [4]byte{0x1, 0x2, 0x3, 0x4})
+	require.ErrorContains(t, err, "is larger than")
+
+	// the request is canceled with the context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = resolver.ResolveSelector(ctx, [4]byte{0x1, 0x2, 0x3, 0x4})
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAbi_DecodeCalldataResolver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the first signature has the same selector but other arguments
+		fmt.Fprint(w, `{"results":[{"text_signature":"many_msg_babbage(bytes1)"},{"text_signature":"transfer(address,uint256)"}]}`)
+	}))
+	defer srv.Close()
+
+	abi, err := NewABIFromList([]string{"function approve(address spender, uint256 amount) returns (bool)"})
+	require.NoError(t, err)
+
+	calldata, err := EncodeWithSignature("transfer(address,uint256)", ethgo.Address{0x1}, big.NewInt(10))
+	require.NoError(t, err)
+
+	_, _, err = abi.DecodeCalldata(calldata)
+	require.Error(t, err)
+
+	abi.Resolver = NewFourByteResolver(srv.URL)
+	name, args, err := abi.DecodeCalldata(calldata)
+	require.NoError(t, err)
+	require.Equal(t, "transfer", name)
+	require.Equal(t, map[string]interface{}{
+		"0": ethgo.Address{0x1},
+		"1": big.NewInt(10),
+	}, args)
+}
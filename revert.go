@@ -3,9 +3,27 @@ package abi
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 )
 
-var revertId = []byte{0x8, 0xC3, 0x79, 0xA0}
+var (
+	revertId = []byte{0x8, 0xC3, 0x79, 0xA0}
+	panicId  = []byte{0x4E, 0x48, 0x7B, 0x71}
+)
+
+// panicReasons are the reasons of the panic codes emitted by solidity
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized internal function",
+}
 
 func UnpackRevertError(b []byte) (string, error) {
 	if !bytes.HasPrefix(b, revertId) {
@@ -21,3 +39,82 @@ func UnpackRevertError(b []byte) (string, error) {
 	revVal := vals.(map[string]interface{})["0"].(string)
 	return revVal, nil
 }
+
+// Revert is the decoded revert data of a failed call
+type Revert struct {
+	// Name is the name of the error, either Error, Panic or the
+	// name of a custom error
+	Name string
+
+	// Reason is the message of an Error(string) revert or the
+	// description of the panic code
+	Reason string
+
+	// Code is the panic code of a Panic(uint256) revert
+	Code *big.Int
+
+	// Args are the decoded arguments of the error
+	Args map[string]interface{}
+
+	// Error is the custom error of the abi that matched the revert
+	Error *Error
+}
+
+// DecodeRevert decodes the revert data of a failed call. It recognizes
+// the Error(string) and Panic(uint256) builtin errors and the custom
+// errors of the abi, which can be nil
+func DecodeRevert(a *ABI, data []byte) (*Revert, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("revert data too short, expected at least 4 bytes but found %d", len(data))
+	}
+	selector := data[:4]
+
+	if bytes.Equal(selector, revertId) {
+		reason, err := UnpackRevertError(data)
+		if err != nil {
+			return nil, err
+		}
+		return &Revert{
+			Name:   "Error",
+			Reason: reason,
+			Args:   map[string]interface{}{"0": reason},
+		}, nil
+	}
+
+	if bytes.Equal(selector, panicId) {
+		vals, err := Decode(MustNewType("tuple(uint256)"), data[4:])
+		if err != nil {
+			return nil, err
+		}
+		code := vals.(map[string]interface{})["0"].(*big.Int)
+
+		reason, ok := panicReasons[code.Uint64()]
+		if !ok || !code.IsUint64() {
+			reason = fmt.Sprintf("unknown panic code 0x%x", code)
+		}
+		return &Revert{
+			Name:   "Panic",
+			Reason: reason,
+			Code:   code,
+			Args:   map[string]interface{}{"0": code},
+		}, nil
+	}
+
+	if a != nil {
+		for _, e := range a.Errors {
+			if !bytes.Equal(selector, e.ID()) {
+				continue
+			}
+			vals, err := Decode(e.Inputs, data[4:])
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode error %s: %v", e.Name, err)
+			}
+			return &Revert{
+				Name:  e.Name,
+				Args:  vals.(map[string]interface{}),
+				Error: e,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown error selector 0x%x", selector)
+}
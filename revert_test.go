@@ -1,9 +1,12 @@
 package abi
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
 )
 
 func TestUnpackRevertError(t *testing.T) {
@@ -16,3 +19,52 @@ func TestUnpackRevertError(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "revert reason", reason)
 }
+
+func TestDecodeRevert(t *testing.T) {
+	data := "08c379a00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000d72657665727420726561736f6e00000000000000000000000000000000000000"
+
+	res, err := DecodeRevert(nil, mustDecodeHex(data))
+	require.NoError(t, err)
+	require.Equal(t, "Error", res.Name)
+	require.Equal(t, "revert reason", res.Reason)
+
+	// panic with a known code
+	res, err = DecodeRevert(nil, mustDecodeHex("0x4e487b71"+"0000000000000000000000000000000000000000000000000000000000000011"))
+	require.NoError(t, err)
+	require.Equal(t, "Panic", res.Name)
+	require.Equal(t, big.NewInt(0x11), res.Code)
+	require.Equal(t, "arithmetic overflow or underflow", res.Reason)
+
+	res, err = DecodeRevert(nil, mustDecodeHex("0x4e487b71"+"00000000000000000000000000000000000000000000000000000000000000ff"))
+	require.NoError(t, err)
+	require.Equal(t, "unknown panic code 0xff", res.Reason)
+
+	// custom error
+	abi, err := NewABIFromList([]string{
+		"error InsufficientBalance(address owner, uint256 balance)",
+	})
+	require.NoError(t, err)
+
+	custom := abi.GetError("InsufficientBalance")
+	require.Equal(t, "InsufficientBalance(address,uint256)", custom.Sig())
+
+	args := map[string]interface{}{
+		"owner":   ethgo.Address{0x1},
+		"balance": big.NewInt(10),
+	}
+	input, err := Encode(args, custom.Inputs)
+	require.NoError(t, err)
+
+	res, err = DecodeRevert(abi, append(custom.ID(), input...))
+	require.NoError(t, err)
+	require.Equal(t, "InsufficientBalance", res.Name)
+	require.Equal(t, custom, res.Error)
+	require.Equal(t, args, res.Args)
+
+	// unknown custom errors
+	_, err = DecodeRevert(nil, append(custom.ID(), input...))
+	require.Error(t, err)
+
+	_, err = DecodeRevert(abi, []byte{0x1})
+	require.Error(t, err)
+}
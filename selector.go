@@ -0,0 +1,132 @@
+package abi
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// MethodID returns the 4 bytes selector of the method signature
+// (i.e. 'transfer(address,uint256)')
+func MethodID(signature string) [4]byte {
+	var id [4]byte
+	copy(id[:], keccak256([]byte(signature)))
+	return id
+}
+
+// EventTopic returns the topic of the event signature, the first topic
+// of the logs of non anonymous events
+// (i.e. 'Transfer(address,address,uint256)')
+func EventTopic(signature string) [32]byte {
+	var topic [32]byte
+	copy(topic[:], keccak256([]byte(signature)))
+	return topic
+}
+
+// EncodeWithSelector returns the calldata with the selector followed by
+// the values encoded in standard mode with the types in order, like the
+// abi.encodeWithSelector builtin of solidity
+func EncodeWithSelector(selector [4]byte, types []*Type, values []interface{}) ([]byte, error) {
+	elems := make([]*TupleElem, len(types))
+	for i, typ := range types {
+		elems[i] = &TupleElem{Elem: typ}
+	}
+	return encodeWithSelector(selector, NewTupleType(elems), values)
+}
+
+// EncodeWithSignature returns the calldata of a call to the method
+// signature with the values, like the abi.encodeWithSignature builtin of
+// solidity. The signature is normalized before hashing so it may include
+// argument names and the 'function' prefix
+func EncodeWithSignature(signature string, values ...interface{}) ([]byte, error) {
+	method, err := NewMethod(signature)
+	if err != nil {
+		return nil, err
+	}
+	return encodeWithSelector(MethodID(method.Sig()), method.Inputs, values)
+}
+
+func encodeWithSelector(selector [4]byte, t *Type, values []interface{}) ([]byte, error) {
+	if len(values) != len(t.TupleElems()) {
+		return nil, fmt.Errorf("expected %d values but found %d", len(t.TupleElems()), len(values))
+	}
+	data, err := Encode(values, t)
+	if err != nil {
+		return nil, err
+	}
+	return append(selector[:], data...), nil
+}
+
+// SplitCalldata splits the calldata into the method selector and
+// the encoded arguments
+func SplitCalldata(calldata []byte) ([4]byte, []byte, error) {
+	var id [4]byte
+	if len(calldata) < 4 {
+		return id, nil, fmt.Errorf("calldata too short, expected at least 4 bytes but found %d", len(calldata))
+	}
+	copy(id[:], calldata[:4])
+	return id, calldata[4:], nil
+}
+
+// MatchesSelector returns whether the calldata starts with the selector
+// of the method signature. The signature is normalized before hashing
+// so it may include argument names and the 'function' prefix
+func MatchesSelector(signature string, calldata []byte) (bool, error) {
+	id, err := selectorOf(signature)
+	if err != nil {
+		return false, err
+	}
+	selector, _, err := SplitCalldata(calldata)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(id[:], selector[:]), nil
+}
+
+// selectorOf parses the method signature and returns its selector
+func selectorOf(signature string) ([4]byte, error) {
+	method, err := NewMethod(signature)
+	if err != nil {
+		return [4]byte{}, err
+	}
+	return MethodID(method.Sig()), nil
+}
+
+// SameSelector returns whether both method signatures have the same selector
+func SameSelector(sigA, sigB string) (bool, error) {
+	idA, err := selectorOf(sigA)
+	if err != nil {
+		return false, err
+	}
+	idB, err := selectorOf(sigB)
+	if err != nil {
+		return false, err
+	}
+	return idA == idB, nil
+}
+
+// SelectorHex returns the selector of the method signature as
+// a 0x prefixed hex string
+func SelectorHex(signature string) (string, error) {
+	id, err := selectorOf(signature)
+	if err != nil {
+		return "", err
+	}
+	return encodeHex(id[:]), nil
+}
+
+// GuessFunction returns the method of the json abi with the selector of
+// the calldata. The arguments can be decoded with the inputs of the method
+func GuessFunction(abiJSON []byte, calldata []byte) (*Method, error) {
+	a, err := NewABI(string(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+	selector, _, err := SplitCalldata(calldata)
+	if err != nil {
+		return nil, err
+	}
+	if m := a.GetMethodByID(selector); m != nil {
+		return m, nil
+	}
+	return nil, fmt.Errorf("no method found for selector 0x%x", selector)
+}
@@ -0,0 +1,130 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestMethodID(t *testing.T) {
+	require.Equal(t, [4]byte{0xa9, 0x05, 0x9c, 0xbb}, MethodID("transfer(address,uint256)"))
+}
+
+func TestEventTopic(t *testing.T) {
+	topic := EventTopic("Transfer(address,address,uint256)")
+	require.Equal(t, "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", encodeHex(topic[:]))
+
+	evnt := MustNewEvent("event Transfer(address indexed from, address indexed to, uint256 value)")
+	require.Equal(t, evnt.ID(), ethgo.Hash(topic))
+}
+
+func TestEncodeWithSignature(t *testing.T) {
+	to := ethgo.Address{0x1}
+	amount := big.NewInt(100)
+
+	expected, err := MustNewMethod("function transfer(address,uint256)").Encode([]interface{}{to, amount})
+	require.NoError(t, err)
+
+	res, err := EncodeWithSignature("transfer(address,uint256)", to, amount)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	res, err = EncodeWithSelector(MethodID("transfer(address,uint256)"), []*Type{MustNewType("address"), MustNewType("uint256")}, []interface{}{to, amount})
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+
+	// no arguments
+	res, err = EncodeWithSignature("totalSupply()")
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x18, 0x16, 0x0d, 0xdd}, res)
+
+	_, err = EncodeWithSignature("transfer(address,uint256)", to)
+	require.EqualError(t, err, "expected 2 values but found 1")
+}
+
+func TestSplitCalldata(t *testing.T) {
+	id, args, err := SplitCalldata([]byte{0x1, 0x2, 0x3, 0x4, 0x5})
+	require.NoError(t, err)
+	require.Equal(t, [4]byte{0x1, 0x2, 0x3, 0x4}, id)
+	require.Equal(t, []byte{0x5}, args)
+
+	_, _, err = SplitCalldata([]byte{0x1, 0x2})
+	require.Error(t, err)
+}
+
+func TestMatchesSelector(t *testing.T) {
+	calldata := mustDecodeHex("0xa9059cbb" +
+		"0000000000000000000000000100000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000001")
+
+	ok, err := MatchesSelector("transfer(address,uint256)", calldata)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = MatchesSelector("function transfer(address to, uint256 amount)", calldata)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = MatchesSelector("approve(address,uint256)", calldata)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = MatchesSelector("transfer(address,uint256)", calldata[:3])
+	require.Error(t, err)
+
+	_, err = MatchesSelector("transfer(address,foo)", calldata)
+	require.Error(t, err)
+}
+
+func TestSameSelector(t *testing.T) {
+	// known collision
+	ok, err := SameSelector("burn(uint256)", "collate_propagate_storage(bytes16)")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = SameSelector("burn(uint256)", "burn(uint256 amount)")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = SameSelector("burn(uint256)", "mint(uint256)")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	_, err = SameSelector("burn(uint256)", "burn(foo)")
+	require.Error(t, err)
+
+	sel, err := SelectorHex("collate_propagate_storage(bytes16)")
+	require.NoError(t, err)
+	require.Equal(t, "0x42966c68", sel)
+}
+
+func TestGuessFunction(t *testing.T) {
+	abiJSON := []byte(`[
+		{"type": "function", "name": "transfer", "inputs": [{"name": "to", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"type": "bool"}]},
+		{"type": "function", "name": "approve", "inputs": [{"name": "spender", "type": "address"}, {"name": "amount", "type": "uint256"}], "outputs": [{"type": "bool"}]}
+	]`)
+
+	calldata := mustDecodeHex("0xa9059cbb" +
+		"0000000000000000000000000100000000000000000000000000000000000000" +
+		"0000000000000000000000000000000000000000000000000000000000000064")
+
+	m, err := GuessFunction(abiJSON, calldata)
+	require.NoError(t, err)
+	require.Equal(t, "transfer", m.Name)
+
+	// the arguments are decoded with the inputs of the method
+	args, err := Decode(m.Inputs, calldata[4:])
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"to":     ethgo.Address{0x1},
+		"amount": big.NewInt(100),
+	}, args)
+
+	_, err = GuessFunction(abiJSON, mustDecodeHex("0x01020304"))
+	require.Error(t, err)
+
+	_, err = GuessFunction(abiJSON, calldata[:3])
+	require.Error(t, err)
+}
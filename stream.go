@@ -0,0 +1,253 @@
+package abi
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+)
+
+// EncodePackedFromChan encodes the values received on the channel as the
+// packed elements of an array of type elem until the channel is closed. It returns
+// the error of the context if it is done before the channel is closed
+func EncodePackedFromChan(ctx context.Context, elem *Type, ch <-chan interface{}) ([]byte, error) {
+	opts := &EncodeOptions{}
+
+	var ret []byte
+	for indx := 0; ; indx++ {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case v, ok := <-ch:
+			if !ok {
+				return ret, nil
+			}
+			if v == nil {
+				return nil, fmt.Errorf("nil value at index %d", indx)
+			}
+			w := &appendWriter{buf: ret}
+			if err := encodePackedTo(w, reflect.ValueOf(v), elem, opts, true); err != nil {
+				return nil, err
+			}
+			ret = w.buf
+		}
+	}
+}
+
+// EncodePackedTo writes the packed encoding of the value to w and returns
+// the number of bytes written. Tuples and arrays are written one element
+// at a time and bytes and strings are written without copying them, so
+// large values are not materialized in memory
+func EncodePackedTo(w io.Writer, v interface{}, t *Type) (int64, error) {
+	cw := &countWriter{w: w}
+	err := encodePackedTo(cw, reflect.ValueOf(v), t, &EncodeOptions{}, false)
+	return cw.n, err
+}
+
+// countWriter counts the bytes written to the underlying writer
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Decoder decodes packed values read from a reader. Static values are
+// read eagerly with their exact size, while the dynamic value at the end
+// of the input is only read when requested
+type Decoder struct {
+	r      io.Reader
+	opts   *DecodeOptions
+	offset int64
+}
+
+// NewDecoder returns a decoder that reads from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: r, opts: &DecodeOptions{}}
+}
+
+// Offset returns the number of bytes read from the reader
+func (d *Decoder) Offset() int64 {
+	return d.offset
+}
+
+// Decode reads and decodes the next value of a static type. It returns
+// io.EOF if the reader is consumed and io.ErrUnexpectedEOF if the reader
+// ends in the middle of the value
+func (d *Decoder) Decode(t *Type) (interface{}, error) {
+	size, ok := t.ByteSize()
+	if !ok {
+		return nil, fmt.Errorf("type '%s' is dynamic, use DecodeTail to decode the rest of the input", t.String())
+	}
+	buf := make([]byte, size)
+	n, err := io.ReadFull(d.r, buf)
+	d.offset += int64(n)
+	if err != nil {
+		return nil, err
+	}
+	val, _, err := decodePacked(t, buf, d.opts)
+	if err != nil {
+		return nil, withOffset(err, int(d.offset))
+	}
+	return val, nil
+}
+
+// DecodeTail reads the rest of the input and decodes it with the type,
+// which is usually dynamic like bytes or a tuple ending in a dynamic value
+func (d *Decoder) DecodeTail(t *Type) (interface{}, error) {
+	buf, err := io.ReadAll(d.r)
+	d.offset += int64(len(buf))
+	if err != nil {
+		return nil, err
+	}
+	return DecodePackedWithOptions(t, buf, d.opts)
+}
+
+// Tail returns the reader with the rest of the input to consume a large
+// dynamic value (i.e. bytes) without buffering it
+func (d *Decoder) Tail() io.Reader {
+	return d.r
+}
+
+// PackedDecoder decodes the elements of a packed array one at a time
+type PackedDecoder struct {
+	elem *Type
+	data []byte
+	opts *DecodeOptions
+}
+
+// NewPackedDecoder returns a decoder that iterates over the packed
+// elements of type elem in the input
+func NewPackedDecoder(elem *Type, input []byte) *PackedDecoder {
+	return &PackedDecoder{elem: elem, data: input, opts: &DecodeOptions{}}
+}
+
+// More returns whether there is input left to decode
+func (d *PackedDecoder) More() bool {
+	return len(d.data) > 0
+}
+
+// Next decodes the next element. It returns io.EOF once the input is consumed
+func (d *PackedDecoder) Next() (interface{}, error) {
+	if !d.More() {
+		return nil, io.EOF
+	}
+	val, tail, err := decodeArrayElemPacked(d.elem, d.data, d.opts)
+	if err != nil {
+		return nil, err
+	}
+	d.data = tail
+	return val, nil
+}
+
+// NextInto decodes the next element into the value pointed by out.
+// It returns io.EOF once the input is consumed
+func (d *PackedDecoder) NextInto(out interface{}) error {
+	// native integers are read directly without boxing the value
+	if ptr, ok := out.(*uint64); ok && d.elem.Kind() == KindUInt && d.elem.Size() <= 64 {
+		size := d.elem.Size() / 8
+		word, _ := packedElemSize(d.elem, d.opts.TightArrays)
+		if !d.More() {
+			return io.EOF
+		}
+		if len(d.data) < word {
+			return fmt.Errorf("Input kind '%s' requires length %d, but input has %d", d.elem.Kind(), word, len(d.data))
+		}
+		*ptr = readUint64(d.data[word-size : word])
+		d.data = d.data[word:]
+		return nil
+	}
+
+	dst := reflect.ValueOf(out)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return fmt.Errorf("expected a non nil pointer but found %T", out)
+	}
+	val, err := d.Next()
+	if err != nil {
+		return err
+	}
+	return setValue(dst.Elem(), reflect.ValueOf(val))
+}
+
+// readUint64 reads a big endian unsigned number of up to 8 bytes
+func readUint64(b []byte) uint64 {
+	var buf [8]byte
+	copy(buf[8-len(b):], b)
+	return binary.BigEndian.Uint64(buf[:])
+}
+
+// setValue assigns a decoded value to dst converting between
+// the numeric types as long as the value fits
+func setValue(dst reflect.Value, val reflect.Value) error {
+	if val.Type().AssignableTo(dst.Type()) {
+		dst.Set(val)
+		return nil
+	}
+
+	if num, ok := val.Interface().(*big.Int); ok {
+		switch dst.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if !num.IsUint64() || dst.OverflowUint(num.Uint64()) {
+				return fmt.Errorf("value %s overflows %s", num.String(), dst.Type())
+			}
+			dst.SetUint(num.Uint64())
+			return nil
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if !num.IsInt64() || dst.OverflowInt(num.Int64()) {
+				return fmt.Errorf("value %s overflows %s", num.String(), dst.Type())
+			}
+			dst.SetInt(num.Int64())
+			return nil
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch val.Kind() {
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if dst.OverflowUint(val.Uint()) {
+				return fmt.Errorf("value %d overflows %s", val.Uint(), dst.Type())
+			}
+			dst.SetUint(val.Uint())
+			return nil
+
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if val.Int() < 0 || dst.OverflowUint(uint64(val.Int())) {
+				return fmt.Errorf("value %d overflows %s", val.Int(), dst.Type())
+			}
+			dst.SetUint(uint64(val.Int()))
+			return nil
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch val.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if dst.OverflowInt(val.Int()) {
+				return fmt.Errorf("value %d overflows %s", val.Int(), dst.Type())
+			}
+			dst.SetInt(val.Int())
+			return nil
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if val.Uint() > uint64(1<<63-1) || dst.OverflowInt(int64(val.Uint())) {
+				return fmt.Errorf("value %d overflows %s", val.Uint(), dst.Type())
+			}
+			dst.SetInt(int64(val.Uint()))
+			return nil
+		}
+	}
+
+	if val.Type().ConvertibleTo(dst.Type()) && val.Kind() == dst.Kind() {
+		dst.Set(val.Convert(dst.Type()))
+		return nil
+	}
+	return fmt.Errorf("cannot assign %s to %s", val.Type(), dst.Type())
+}
@@ -0,0 +1,163 @@
+package abi
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestPackedDecoder_NextInto(t *testing.T) {
+	input, err := EncodePacked([]uint64{1, 2, 3}, MustNewType("uint64[]"))
+	require.NoError(t, err)
+
+	dec := NewPackedDecoder(MustNewType("uint64"), input)
+
+	res := []uint64{}
+	for dec.More() {
+		var val uint64
+		require.NoError(t, dec.NextInto(&val))
+		res = append(res, val)
+	}
+	require.Equal(t, []uint64{1, 2, 3}, res)
+
+	var val uint64
+	require.Equal(t, io.EOF, dec.NextInto(&val))
+}
+
+func TestPackedDecoder_NextIntoConvert(t *testing.T) {
+	input, err := EncodePacked([]*big.Int{big.NewInt(1), big.NewInt(300)}, MustNewType("uint256[]"))
+	require.NoError(t, err)
+
+	dec := NewPackedDecoder(MustNewType("uint256"), input)
+
+	var a uint16
+	require.NoError(t, dec.NextInto(&a))
+	require.Equal(t, uint16(1), a)
+
+	// 300 does not fit in an uint8
+	var b uint8
+	require.Error(t, dec.NextInto(&b))
+
+	require.Error(t, dec.NextInto(a))
+}
+
+func TestEncodePackedFromChan(t *testing.T) {
+	ch := make(chan interface{}, 3)
+	ch <- uint16(1)
+	ch <- big.NewInt(2)
+	ch <- "0x3"
+	close(ch)
+
+	res, err := EncodePackedFromChan(context.Background(), MustNewType("uint16"), ch)
+	require.NoError(t, err)
+	require.Len(t, res, 96)
+	require.Equal(t, byte(0x1), res[31])
+	require.Equal(t, byte(0x2), res[63])
+	require.Equal(t, byte(0x3), res[95])
+}
+
+func TestEncodePackedFromChan_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan interface{})
+
+	go func() {
+		ch <- uint16(1)
+		ch <- uint16(2)
+		// the channel is never closed
+		cancel()
+	}()
+
+	_, err := EncodePackedFromChan(ctx, MustNewType("uint16"), ch)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+type chunkWriter struct {
+	chunks [][]byte
+}
+
+func (c *chunkWriter) Write(p []byte) (int, error) {
+	c.chunks = append(c.chunks, p)
+	return len(p), nil
+}
+
+func TestEncodePackedTo(t *testing.T) {
+	typ := MustNewType("tuple(address to, uint16[2] ids, tuple(string name, bytes data)[] items)")
+	payload := bytes.Repeat([]byte{0x1}, 1024)
+	value := map[string]interface{}{
+		"to":  ethgo.Address{0x1},
+		"ids": [2]uint16{1, 2},
+		"items": []map[string]interface{}{
+			{"name": "a", "data": payload},
+		},
+	}
+
+	expected, err := EncodePacked(value, typ)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	n, err := EncodePackedTo(&buf, value, typ)
+	require.NoError(t, err)
+	require.Equal(t, int64(len(expected)), n)
+	require.Equal(t, expected, buf.Bytes())
+
+	// the bytes are written without copying them
+	w := &chunkWriter{}
+	_, err = EncodePackedTo(w, value, typ)
+	require.NoError(t, err)
+	require.Len(t, w.chunks, 5)
+	require.Same(t, &payload[0], &w.chunks[4][0])
+
+	// the errors have the path of the value
+	_, err = EncodePackedTo(io.Discard, map[string]interface{}{
+		"to":    ethgo.Address{0x1},
+		"ids":   [2]uint16{1, 2},
+		"items": []map[string]interface{}{{"name": "a", "data": 1}},
+	}, typ)
+	require.EqualError(t, err, "items[0].data: failed to encode int as bytes")
+}
+
+func TestDecoder(t *testing.T) {
+	types := []*Type{MustNewType("address"), MustNewType("uint16[2]"), MustNewType("bytes")}
+	input, err := EncodePackedValues(types, ethgo.Address{0x1}, [2]uint16{1, 2}, []byte("payload"))
+	require.NoError(t, err)
+
+	dec := NewDecoder(bytes.NewReader(input))
+
+	addr, err := dec.Decode(types[0])
+	require.NoError(t, err)
+	require.Equal(t, ethgo.Address{0x1}, addr)
+
+	ids, err := dec.Decode(types[1])
+	require.NoError(t, err)
+	require.Equal(t, [2]uint16{1, 2}, ids)
+	require.Equal(t, int64(20+64), dec.Offset())
+
+	// dynamic types are decoded from the rest of the input
+	_, err = dec.Decode(types[2])
+	require.Error(t, err)
+
+	tail, err := io.ReadAll(dec.Tail())
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), tail)
+
+	_, err = dec.Decode(types[0])
+	require.ErrorIs(t, err, io.EOF)
+
+	// the input ends in the middle of a value
+	dec = NewDecoder(bytes.NewReader(input[:30]))
+	_, err = dec.Decode(MustNewType("tuple(address,uint256)"))
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	dec = NewDecoder(bytes.NewReader(input))
+	_, err = dec.Decode(types[0])
+	require.NoError(t, err)
+
+	val, err := dec.DecodeTail(MustNewType("tuple(uint16[2] ids, string rest)"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"ids": [2]uint16{1, 2}, "rest": "payload"}, val)
+}
@@ -0,0 +1,191 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// TypeFromStruct builds the tuple type of a struct. The elements are named
+// after the 'abi' tag or the lowercase field name, like in the encoding,
+// and the solidity type is either given as the second value of the tag
+// (i.e. `abi:"amount,uint256"`) or derived from the go type of the field
+func TypeFromStruct(v interface{}) (*Type, error) {
+	typ := reflect.TypeOf(v)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct but found %v", typ)
+	}
+	return typeFromStruct(typ)
+}
+
+func typeFromStruct(typ reflect.Type) (*Type, error) {
+	elems := []*TupleElem{}
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+
+		tagValue := f.Tag.Get("abi")
+		if tagValue == "-" {
+			continue
+		}
+
+		name, typStr := parseAbiTag(tagValue)
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		var elem *Type
+		var err error
+		if typStr != "" {
+			elem, err = NewType(typStr)
+		} else {
+			elem, err = typeFromGoType(f.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Name, err)
+		}
+		elems = append(elems, &TupleElem{
+			Name: name,
+			Elem: elem,
+		})
+	}
+	return NewTupleType(elems), nil
+}
+
+// typeFromGoType derives the solidity type of a go type. Types without a
+// single matching solidity type (i.e. *big.Int) return an error
+func typeFromGoType(typ reflect.Type) (*Type, error) {
+	switch typ {
+	case addressT:
+		return NewType("address")
+	case bigIntT:
+		return nil, fmt.Errorf("cannot derive the size of *big.Int, use the abi tag")
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return NewType("bool")
+
+	case reflect.String:
+		return NewType("string")
+
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return NewType(fmt.Sprintf("uint%d", typ.Bits()))
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return NewType(fmt.Sprintf("int%d", typ.Bits()))
+
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return NewType("bytes")
+		}
+		elem, err := typeFromGoType(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Type{kind: KindSlice, elem: elem, t: reflect.SliceOf(elem.t)}, nil
+
+	case reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 && typ.Len() <= 32 {
+			return NewType(fmt.Sprintf("bytes%d", typ.Len()))
+		}
+		elem, err := typeFromGoType(typ.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Type{kind: KindArray, elem: elem, size: typ.Len(), t: reflect.ArrayOf(typ.Len(), elem.t)}, nil
+
+	case reflect.Struct:
+		return typeFromStruct(typ)
+
+	case reflect.Ptr:
+		if typ.Elem().Kind() == reflect.Struct {
+			return typeFromStruct(typ.Elem())
+		}
+	}
+	return nil, fmt.Errorf("no solidity type for go type %s, use the abi tag", typ)
+}
+
+// parseAbiTag splits the 'abi' struct tag into the name and the
+// optional solidity type
+func parseAbiTag(tag string) (string, string) {
+	name, typ, _ := strings.Cut(tag, ",")
+	return strings.TrimSpace(name), strings.TrimSpace(typ)
+}
+
+// checkGoType returns an error if values of the go type cannot be
+// encoded with the type t. Interfaces are only checked when encoding
+func checkGoType(typ reflect.Type, t *Type) error {
+	if typ.Kind() == reflect.Interface || typ == preEncodedT {
+		return nil
+	}
+	if hook, ok := getTypeHook(typ); ok && hook.Kind == t.Kind() {
+		return nil
+	}
+
+	ok := false
+	switch t.Kind() {
+	case KindBool:
+		ok = typ.Kind() == reflect.Bool
+
+	case KindString:
+		ok = typ.Kind() == reflect.String
+
+	case KindInt, KindUInt:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float64, reflect.String:
+			ok = true
+		case reflect.Ptr:
+			ok = typ == bigIntT || typ == uint256T || checkGoType(typ.Elem(), t) == nil
+		case reflect.Array:
+			ok = typ == uint256ValT
+		}
+
+	case KindAddress:
+		ok = typ.Kind() == reflect.String || isByteList(typ) || typ.Implements(addressProviderT)
+
+	case KindBytes:
+		ok = typ.Kind() == reflect.String || isByteList(typ) || typ.Kind() == reflect.Func
+
+	case KindFixedBytes, KindFunction:
+		ok = typ.Kind() == reflect.String || isByteList(typ) || typ == bigIntT
+
+	case KindFixedPoint:
+		switch typ.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64, reflect.String:
+			ok = true
+		case reflect.Ptr:
+			ok = typ == ratT || typ == bigIntT
+		}
+
+	case KindSlice, KindArray:
+		if typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array {
+			return checkGoType(typ.Elem(), t.Elem())
+		}
+
+	case KindTuple:
+		switch typ.Kind() {
+		case reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+			ok = true
+		case reflect.Ptr:
+			ok = typ.Elem().Kind() == reflect.Struct
+		}
+	}
+	if !ok {
+		return fmt.Errorf("go type %s cannot be encoded as %s", typ, t.String())
+	}
+	return nil
+}
+
+func isByteList(typ reflect.Type) bool {
+	return (typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) && typ.Elem().Kind() == reflect.Uint8
+}
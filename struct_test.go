@@ -0,0 +1,68 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
+)
+
+func TestTypeFromStruct(t *testing.T) {
+	type Transfer struct {
+		Amount *big.Int      `abi:"amount,uint256"`
+		To     ethgo.Address `abi:"to"`
+	}
+
+	typ, err := TypeFromStruct(Transfer{})
+	require.NoError(t, err)
+	require.Equal(t, "tuple(uint256 amount,address to)", typ.Format(true))
+
+	// the derived type encodes the struct
+	res, err := EncodePacked(&Transfer{Amount: big.NewInt(1), To: ethgo.Address{0x1}}, typ)
+	require.NoError(t, err)
+	require.Len(t, res, 52)
+}
+
+func TestTypeFromStruct_GoTypes(t *testing.T) {
+	type Inner struct {
+		Flag bool
+	}
+	type Obj struct {
+		A uint8
+		B int64
+		C []byte
+		D [4]byte
+		E string
+		F []uint16
+		G [2]Inner
+		H []ethgo.Address
+		I *big.Int `abi:",int128"`
+		J uint32   `abi:"-"`
+		k uint32
+	}
+
+	typ, err := TypeFromStruct(&Obj{})
+	require.NoError(t, err)
+	require.Equal(t, "tuple(uint8 a,int64 b,bytes c,bytes4 d,string e,uint16[] f,tuple(bool flag)[2] g,address[] h,int128 i)", typ.Format(true))
+}
+
+func TestTypeFromStruct_Errors(t *testing.T) {
+	_, err := TypeFromStruct(struct {
+		Amount *big.Int
+	}{})
+	require.Error(t, err)
+
+	_, err = TypeFromStruct(struct {
+		Value float64
+	}{})
+	require.Error(t, err)
+
+	_, err = TypeFromStruct(struct {
+		Value uint64 `abi:"value,foo"`
+	}{})
+	require.Error(t, err)
+
+	_, err = TypeFromStruct(1)
+	require.Error(t, err)
+}
@@ -8,8 +8,28 @@ import (
 	"github.com/umbracle/ethgo"
 )
 
+// IndexedHash is the value of an indexed dynamic argument of an event
+// (string, bytes, arrays and tuples). The log only includes the keccak256
+// hash of the encoded value so it cannot be decoded back
+type IndexedHash ethgo.Hash
+
+// String implements the fmt.Stringer interface
+func (h IndexedHash) String() string {
+	return ethgo.Hash(h).String()
+}
+
 // ParseLog parses an event log
 func ParseLog(args *Type, log *ethgo.Log) (map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log does not have topics")
+	}
+	return DecodeLogArgs(args, log.Topics[1:], log.Data)
+}
+
+// DecodeLogArgs decodes the indexed arguments from the topics and the rest
+// from the data of a log. Unlike Event.DecodeLog, the topics only include
+// the indexed arguments and not the signature topic of the event
+func DecodeLogArgs(args *Type, topics []ethgo.Hash, data []byte) (map[string]interface{}, error) {
 	var indexed, nonIndexed []*TupleElem
 
 	for _, arg := range args.TupleElems() {
@@ -21,14 +41,14 @@ func ParseLog(args *Type, log *ethgo.Log) (map[string]interface{}, error) {
 	}
 
 	// decode indexed fields
-	indexedObjs, err := ParseTopics(&Type{kind: KindTuple, tuple: indexed}, log.Topics[1:])
+	indexedObjs, err := ParseTopics(&Type{kind: KindTuple, tuple: indexed}, topics)
 	if err != nil {
 		return nil, err
 	}
 
 	var nonIndexedObjs map[string]interface{}
 	if len(nonIndexed) > 0 {
-		nonIndexedRaw, err := Decode(&Type{kind: KindTuple, tuple: nonIndexed}, log.Data)
+		nonIndexedRaw, err := Decode(&Type{kind: KindTuple, tuple: nonIndexed}, data)
 		if err != nil {
 			return nil, err
 		}
@@ -52,6 +72,32 @@ func ParseLog(args *Type, log *ethgo.Log) (map[string]interface{}, error) {
 	return res, nil
 }
 
+// DecodeEventByName parses the json abi and decodes the topics and data
+// of a log emitted by the event with the given name
+func DecodeEventByName(abiJSON []byte, eventName string, topics [][]byte, data []byte) (map[string]interface{}, error) {
+	abi, err := NewABIFromReader(bytes.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+	event, ok := abi.Events[eventName]
+	if !ok {
+		return nil, fmt.Errorf("event '%s' not found", eventName)
+	}
+
+	log := &ethgo.Log{
+		Data: data,
+	}
+	for indx, topic := range topics {
+		if len(topic) != 32 {
+			return nil, fmt.Errorf("topic %d has length %d, expected 32", indx, len(topic))
+		}
+		var hash ethgo.Hash
+		copy(hash[:], topic)
+		log.Topics = append(log.Topics, hash)
+	}
+	return event.ParseLog(log)
+}
+
 // ParseTopics parses topics from a log event
 func ParseTopics(args *Type, topics []ethgo.Hash) ([]interface{}, error) {
 	if args.kind != KindTuple {
@@ -93,6 +139,9 @@ func ParseTopic(t *Type, topic ethgo.Hash) (interface{}, error) {
 	case KindFixedBytes:
 		return readFixedBytes(t, topic[:])
 
+	case KindString, KindBytes, KindSlice, KindArray, KindTuple:
+		return IndexedHash(topic), nil
+
 	default:
 		return nil, fmt.Errorf("topic parsing for type %s not supported", t.String())
 	}
@@ -118,6 +167,91 @@ func encodeTopic(t *Type, val reflect.Value) (ethgo.Hash, error) {
 	return ethgo.Hash{}, fmt.Errorf("not found")
 }
 
+// IndexedTopic returns the topic of an indexed event argument. Value types
+// are stored in the topic using the standard encoding while strings, bytes,
+// arrays and tuples are stored as the keccak256 hash of their in-place encoding
+func IndexedTopic(t *Type, v interface{}) (ethgo.Hash, error) {
+	var res ethgo.Hash
+
+	var buf []byte
+	var err error
+	switch t.kind {
+	case KindString, KindBytes, KindSlice, KindArray, KindTuple:
+		buf, err = encodeTopicInPlace(reflect.ValueOf(v), t, false)
+		if err != nil {
+			return res, err
+		}
+		buf = keccak256(buf)
+
+	default:
+		buf, err = Encode(v, t)
+		if err != nil {
+			return res, err
+		}
+	}
+	copy(res[:], buf)
+	return res, nil
+}
+
+// encodeTopicInPlace encodes a value using the in-place encoding of indexed
+// arguments. Strings and bytes are not padded unless they are part of
+// an array or a tuple
+func encodeTopicInPlace(v reflect.Value, t *Type, pad bool) ([]byte, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if isNil(v) {
+		return nil, fmt.Errorf("cannot encode nil value as %s", t.String())
+	}
+
+	switch t.kind {
+	case KindString, KindBytes:
+		buf, err := encodePacked(v, t, &EncodeOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if pad {
+			buf = rightPad(buf, (len(buf)+31)/32*32)
+		}
+		return buf, nil
+
+	case KindSlice, KindArray:
+		if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+			return nil, encodeErr(v, t.kind.String())
+		}
+		if t.kind == KindArray && t.size != v.Len() {
+			return nil, fmt.Errorf("array len incompatible")
+		}
+		var res []byte
+		for i := 0; i < v.Len(); i++ {
+			buf, err := encodeTopicInPlace(v.Index(i), t.elem, true)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, buf...)
+		}
+		return res, nil
+
+	case KindTuple:
+		values, err := tupleValues(v, t)
+		if err != nil {
+			return nil, err
+		}
+		var res []byte
+		for i, elem := range t.tuple {
+			buf, err := encodeTopicInPlace(values[i], elem.Elem, true)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, buf...)
+		}
+		return res, nil
+
+	default:
+		return encode(v, t, wordSize, &EncodeOptions{})
+	}
+}
+
 var topicTrue, topicFalse ethgo.Hash
 
 func init() {
@@ -126,7 +260,7 @@ func init() {
 
 func encodeTopicAddress(val reflect.Value) (res ethgo.Hash, err error) {
 	var b []byte
-	b, err = encodeAddress(val)
+	b, err = encodeAddress(val, wordSize)
 	if err != nil {
 		return
 	}
@@ -136,7 +270,7 @@ func encodeTopicAddress(val reflect.Value) (res ethgo.Hash, err error) {
 
 func encodeTopicNum(t *Type, val reflect.Value) (res ethgo.Hash, err error) {
 	var b []byte
-	b, err = encodeNum(val)
+	b, err = encodeNum(val, wordSize)
 	if err != nil {
 		return
 	}
@@ -118,3 +118,127 @@ func TestIntegrationTopics(t *testing.T) {
 		}
 	}
 }
+
+func TestDecodeEventByName(t *testing.T) {
+	abiJSON := []byte(`[
+		{
+			"name": "Transfer",
+			"type": "event",
+			"inputs": [
+				{"indexed": true, "name": "from", "type": "address"},
+				{"indexed": true, "name": "to", "type": "address"},
+				{"indexed": false, "name": "value", "type": "uint256"}
+			]
+		}
+	]`)
+
+	event := MustNewEvent("event Transfer(address indexed from, address indexed to, uint256 value)")
+	id := event.ID()
+
+	from := ethgo.Address{0x1}
+	to := ethgo.Address{0x2}
+
+	topics := [][]byte{
+		id[:],
+		leftPad(from[:], 32),
+		leftPad(to[:], 32),
+	}
+	data, err := Encode(map[string]interface{}{"value": big.NewInt(100)}, MustNewType("tuple(uint256 value)"))
+	require.NoError(t, err)
+
+	res, err := DecodeEventByName(abiJSON, "Transfer", topics, data)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"from":  from,
+		"to":    to,
+		"value": big.NewInt(100),
+	}, res)
+
+	_, err = DecodeEventByName(abiJSON, "Approval", topics, data)
+	require.Error(t, err)
+
+	_, err = DecodeEventByName(abiJSON, "Transfer", [][]byte{id[:4]}, data)
+	require.Error(t, err)
+}
+
+func TestIndexedTopic(t *testing.T) {
+	// value types are left padded
+	addr := ethgo.Address{0x1}
+	topic, err := IndexedTopic(MustNewType("address"), addr)
+	require.NoError(t, err)
+	require.Equal(t, "0x0000000000000000000000000100000000000000000000000000000000000000", topic.String())
+
+	// dynamic types are hashed
+	topic, err = MustNewType("string").IndexedTopic("hello")
+	require.NoError(t, err)
+	require.Equal(t, ethgo.BytesToHash(keccak256([]byte("hello"))), topic)
+
+	// array elements are padded to 32 bytes before hashing
+	topic, err = IndexedTopic(MustNewType("uint8[]"), []uint8{1, 2})
+	require.NoError(t, err)
+
+	expected := keccak256(
+		leftPad([]byte{1}, 32),
+		leftPad([]byte{2}, 32),
+	)
+	require.Equal(t, ethgo.BytesToHash(expected), topic)
+
+	// strings inside tuples are right padded
+	topic, err = IndexedTopic(MustNewType("tuple(string a, uint8 b)"), map[string]interface{}{"a": "hello", "b": uint8(1)})
+	require.NoError(t, err)
+
+	expected = keccak256(
+		rightPad([]byte("hello"), 32),
+		leftPad([]byte{1}, 32),
+	)
+	require.Equal(t, ethgo.BytesToHash(expected), topic)
+}
+
+func TestEventDecodeLog(t *testing.T) {
+	event := MustNewEvent("event Memo(address indexed from, string indexed memo, uint256 value)")
+
+	from := ethgo.Address{0x1}
+	memo, err := IndexedTopic(MustNewType("string"), "hello")
+	require.NoError(t, err)
+
+	topics := []ethgo.Hash{
+		event.ID(),
+		ethgo.BytesToHash(leftPad(from[:], 32)),
+		memo,
+	}
+	data, err := Encode(map[string]interface{}{"value": big.NewInt(100)}, MustNewType("tuple(uint256 value)"))
+	require.NoError(t, err)
+
+	res, err := event.DecodeLog(topics, data)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"from":  from,
+		"memo":  IndexedHash(memo),
+		"value": big.NewInt(100),
+	}, res)
+
+	// decode into a struct
+	var out struct {
+		From  ethgo.Address
+		Memo  IndexedHash
+		Value *big.Int
+	}
+	require.NoError(t, event.ParseLogInto(&ethgo.Log{Topics: topics, Data: data}, &out))
+	require.Equal(t, from, out.From)
+	require.Equal(t, memo.String(), out.Memo.String())
+	require.Equal(t, big.NewInt(100), out.Value)
+
+	// anonymous events do not include the signature topic
+	event.Anonymous = true
+	res, err = event.DecodeLog(topics[1:], data)
+	require.NoError(t, err)
+	require.Equal(t, from, res["from"])
+
+	// the args only take the topics of the indexed arguments
+	res, err = DecodeLogArgs(event.Inputs, topics[1:], data)
+	require.NoError(t, err)
+	require.Equal(t, IndexedHash(memo), res["memo"])
+
+	_, err = ParseLog(event.Inputs, &ethgo.Log{Data: data})
+	require.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/umbracle/ethgo"
 )
@@ -97,6 +98,15 @@ type TupleElem struct {
 	Indexed bool
 }
 
+// tupleElemName returns the name of the tuple element or its position
+// if the element is unnamed
+func tupleElemName(elem *TupleElem, indx int) string {
+	if elem.Name == "" {
+		return strconv.Itoa(indx)
+	}
+	return elem.Name
+}
+
 // Type is an ABI type
 type Type struct {
 	kind  Kind
@@ -105,6 +115,13 @@ type Type struct {
 	tuple []*TupleElem
 	t     reflect.Type
 	itype string
+	label string
+	enum  []string
+	udvt  string
+
+	// decimals and signed are the attributes of the fixed point types
+	decimals int
+	signed   bool
 }
 
 func NewTupleType(inputs []*TupleElem) *Type {
@@ -115,6 +132,44 @@ func NewTupleType(inputs []*TupleElem) *Type {
 	}
 }
 
+// NewTupleTypeFromElems creates a tuple type with the elements in order.
+// It fails if an element has no type or two elements have the same name
+func NewTupleTypeFromElems(elems ...*TupleElem) (*Type, error) {
+	names := map[string]struct{}{}
+	for indx, elem := range elems {
+		if elem == nil || elem.Elem == nil {
+			return nil, fmt.Errorf("tuple element %d has no type", indx)
+		}
+		if elem.Name == "" {
+			continue
+		}
+		if _, ok := names[elem.Name]; ok {
+			return nil, fmt.Errorf("tuple with repeated element '%s'", elem.Name)
+		}
+		names[elem.Name] = struct{}{}
+	}
+	return NewTupleType(append([]*TupleElem{}, elems...)), nil
+}
+
+// NewArrayType creates a fixed size array type (i.e. elem[size])
+func NewArrayType(elem *Type, size int) (*Type, error) {
+	if elem == nil {
+		return nil, fmt.Errorf("array type has no element type")
+	}
+	if size <= 0 {
+		return nil, fmt.Errorf("array size has to be positive but found %d", size)
+	}
+	return &Type{kind: KindArray, elem: elem, size: size, t: reflect.ArrayOf(size, elem.t)}, nil
+}
+
+// NewSliceType creates a dynamic size array type (i.e. elem[])
+func NewSliceType(elem *Type) (*Type, error) {
+	if elem == nil {
+		return nil, fmt.Errorf("slice type has no element type")
+	}
+	return &Type{kind: KindSlice, elem: elem, t: reflect.SliceOf(elem.t)}, nil
+}
+
 func NewTupleTypeFromArgs(inputs []*ArgumentStr) (*Type, error) {
 	elems := []*TupleElem{}
 	for _, i := range inputs {
@@ -146,22 +201,65 @@ func (t *Type) DecodeStruct(input []byte, out interface{}) error {
 	return DecodeStruct(t, input, out)
 }
 
+// IndexedTopic returns the topic of an indexed event argument with this type
+func (t *Type) IndexedTopic(v interface{}) (ethgo.Hash, error) {
+	return IndexedTopic(t, v)
+}
+
 // InternalType returns the internal type
 func (t *Type) InternalType() string {
 	return t.itype
 }
 
+// Label returns the human readable label of the type
+func (t *Type) Label() string {
+	return t.label
+}
+
+// SetLabel sets a human readable label for the type that is included
+// in the encoding and decoding errors of values of this type
+func (t *Type) SetLabel(label string) {
+	t.label = label
+}
+
+// labelErr prefixes the error with the label of the type if it is set
+func (t *Type) labelErr(err error) error {
+	if t.label == "" {
+		return err
+	}
+	return fmt.Errorf("%s (%s): %v", t.label, t.String(), err)
+}
+
 // Encode encodes an object using this type
 func (t *Type) Encode(v interface{}) ([]byte, error) {
 	return Encode(v, t)
 }
 
+// EncodePacked encodes an object using this type in packed mode
+func (t *Type) EncodePacked(v interface{}) ([]byte, error) {
+	return EncodePacked(v, t)
+}
+
+// DecodePacked decodes a packed input using this type
+func (t *Type) DecodePacked(input []byte) (interface{}, error) {
+	return DecodePacked(t, input)
+}
+
+// DecodePackedInto decodes a packed input using this type into the out param
+func (t *Type) DecodePackedInto(input []byte, out interface{}) error {
+	return DecodePackedInto(t, input, out)
+}
+
 func (t *Type) String() string {
 	return t.Format(false)
 }
 
-// String returns the raw representation of the type
+// String returns the raw representation of the type. User defined value
+// types are rendered with their name if the args are included
 func (t *Type) Format(includeArgs bool) string {
+	if includeArgs && t.udvt != "" {
+		return t.udvt
+	}
 	switch t.kind {
 	case KindTuple:
 		rawAux := []string{}
@@ -209,11 +307,40 @@ func (t *Type) Format(includeArgs bool) string {
 	case KindInt:
 		return fmt.Sprintf("int%d", t.size)
 
+	case KindFixedPoint:
+		if t.signed {
+			return fmt.Sprintf("fixed%dx%d", t.size, t.decimals)
+		}
+		return fmt.Sprintf("ufixed%dx%d", t.size, t.decimals)
+
 	default:
 		panic(fmt.Errorf("BUG: abi type not found %s", t.kind.String()))
 	}
 }
 
+// Signature returns the canonical representation of the type used in
+// the signatures, with the tuples expanded as the list of their types
+// and without names (i.e. '(address,(uint256,bytes)[])')
+func (t *Type) Signature() string {
+	switch t.kind {
+	case KindTuple:
+		elems := make([]string, len(t.tuple))
+		for i, elem := range t.tuple {
+			elems[i] = elem.Elem.Signature()
+		}
+		return "(" + strings.Join(elems, ",") + ")"
+
+	case KindArray:
+		return fmt.Sprintf("%s[%d]", t.elem.Signature(), t.size)
+
+	case KindSlice:
+		return t.elem.Signature() + "[]"
+
+	default:
+		return t.Format(false)
+	}
+}
+
 // Elem returns the elem value for slice and arrays
 func (t *Type) Elem() *Type {
 	return t.elem
@@ -229,7 +356,132 @@ func (t *Type) TupleElems() []*TupleElem {
 	return t.tuple
 }
 
-// GoType returns the go type
+// Leaves returns the elementary types in order, flattening tuples
+// and fixed size arrays. Slices are returned as a single leaf
+func (t *Type) Leaves() []*Type {
+	switch t.kind {
+	case KindTuple:
+		res := []*Type{}
+		for _, elem := range t.tuple {
+			res = append(res, elem.Elem.Leaves()...)
+		}
+		return res
+
+	case KindArray:
+		res := []*Type{}
+		for i := 0; i < t.size; i++ {
+			res = append(res, t.elem.Leaves()...)
+		}
+		return res
+
+	default:
+		return []*Type{t}
+	}
+}
+
+// Stripped returns a copy of the type without the names of the tuple
+// elements, the internal types and the labels
+func (t *Type) Stripped() *Type {
+	res := &Type{
+		kind:     t.kind,
+		size:     t.size,
+		t:        t.t,
+		decimals: t.decimals,
+		signed:   t.signed,
+	}
+	if t.elem != nil {
+		res.elem = t.elem.Stripped()
+	}
+	if t.tuple != nil {
+		res.tuple = make([]*TupleElem, len(t.tuple))
+		for i, elem := range t.tuple {
+			res.tuple[i] = &TupleElem{
+				Elem:    elem.Elem.Stripped(),
+				Indexed: elem.Indexed,
+			}
+		}
+	}
+	return res
+}
+
+// ByteSize returns the size in bytes of the type in packed mode, with
+// the elements of the arrays padded to 32 bytes. The second value is
+// false if the type is dynamic and has no fixed size
+func (t *Type) ByteSize() (int, bool) {
+	return packedByteSize(t, false)
+}
+
+// packedByteSize returns the size in bytes of the type in packed mode
+// with either padded or tight array elements
+func packedByteSize(t *Type, tight bool) (int, bool) {
+	switch t.kind {
+	case KindBool:
+		return 1, true
+
+	case KindInt, KindUInt, KindFixedPoint:
+		return t.size / 8, true
+
+	case KindAddress:
+		return 20, true
+
+	case KindFunction:
+		return 24, true
+
+	case KindFixedBytes:
+		return t.size, true
+
+	case KindArray:
+		size, ok := packedElemSize(t.elem, tight)
+		if !ok {
+			return 0, false
+		}
+		return t.size * size, true
+
+	case KindTuple:
+		total := 0
+		for _, elem := range t.tuple {
+			size, ok := packedByteSize(elem.Elem, tight)
+			if !ok {
+				return 0, false
+			}
+			total += size
+		}
+		return total, true
+
+	default:
+		return 0, false
+	}
+}
+
+// packedElemSize returns the size in bytes of an array element in packed
+// mode. Like solidity, the elementary values of arrays are padded to 32
+// bytes unless the elements are tight
+func packedElemSize(t *Type, tight bool) (int, bool) {
+	if tight {
+		return packedByteSize(t, true)
+	}
+	switch t.kind {
+	case KindArray, KindTuple:
+		return packedByteSize(t, false)
+
+	case KindBool, KindInt, KindUInt, KindAddress, KindFunction, KindFixedBytes, KindFixedPoint:
+		return 32, true
+
+	default:
+		return 0, false
+	}
+}
+
+// StandardSize returns the size in bytes of the type in the standard
+// encoding. The second value is false if the type is dynamic, in which
+// case the size is the word with its offset in the head
+func (t *Type) StandardSize() (int, bool) {
+	return getTypeSize(t, wordSize), !t.isDynamicType()
+}
+
+// GoType returns the go type of the values of the type in the standard
+// decoding. The packed decoding returns the integers of up to 64 bits as
+// the smallest native type that fits them (i.e. uint24 as uint32)
 func (t *Type) GoType() reflect.Type {
 	return t.t
 }
@@ -280,6 +532,23 @@ func parseType(arg *ArgumentStr) (string, error) {
 	return fmt.Sprintf("tuple(%s)%s", strings.Join(str, ","), strings.TrimPrefix(arg.Type, "tuple")), nil
 }
 
+// typeCache stores the types parsed by cachedType indexed by their string
+var typeCache sync.Map
+
+// cachedType parses the type string once and reuses the result on
+// later calls. The returned type is shared and must not be modified
+func cachedType(s string) (*Type, error) {
+	if typ, ok := typeCache.Load(s); ok {
+		return typ.(*Type), nil
+	}
+	typ, err := NewType(s)
+	if err != nil {
+		return nil, err
+	}
+	typeCache.Store(s, typ)
+	return typ, nil
+}
+
 // NewTypeFromArgument parses an abi type from an argument
 func NewTypeFromArgument(arg *ArgumentStr) (*Type, error) {
 	str, err := parseType(arg)
@@ -299,6 +568,12 @@ func NewTypeFromArgument(arg *ArgumentStr) (*Type, error) {
 
 func fillIn(typ *Type, arg *ArgumentStr) error {
 	typ.itype = arg.InternalType
+	if values, ok := getEnum(arg.InternalType); ok && typ.kind == KindUInt && typ.size == 8 {
+		typ.enum = values
+	}
+	if arg.InternalType != "" && len(arg.Components) == 0 {
+		fillInUserType(typ, arg.InternalType)
+	}
 
 	if len(arg.Components) == 0 {
 		// no more items, nothing else to do
@@ -349,20 +624,22 @@ func MustNewType(s string) *Type {
 	return t
 }
 
-func getTypeSize(t *Type) int {
+// getTypeSize returns the size in bytes of the head of the type with
+// words of w bytes
+func getTypeSize(t *Type, w int) int {
 	if t.kind == KindArray && !t.elem.isDynamicType() {
 		if t.elem.kind == KindArray || t.elem.kind == KindTuple {
-			return t.size * getTypeSize(t.elem)
+			return t.size * getTypeSize(t.elem, w)
 		}
-		return t.size * 32
+		return t.size * w
 	} else if t.kind == KindTuple && !t.isDynamicType() {
 		total := 0
 		for _, elem := range t.tuple {
-			total += getTypeSize(elem.Elem)
+			total += getTypeSize(elem.Elem, w)
 		}
 		return total
 	}
-	return 32
+	return w
 }
 
 var typeRegexp = regexp.MustCompile("^([[:alpha:]]+)([[:digit:]]*)$")
@@ -482,6 +759,18 @@ func readType(l *lexer) (*Type, error) {
 }
 
 func decodeSimpleType(str string) (*Type, error) {
+	if typ, ok, err := decodeUserType(str); ok {
+		return typ, err
+	}
+	return decodeBuiltinType(str)
+}
+
+// decodeBuiltinType parses an elementary type without the registered
+// user defined value types
+func decodeBuiltinType(str string) (*Type, error) {
+	if typ, ok, err := decodeFixedType(str); ok {
+		return typ, err
+	}
 	match := typeRegexp.FindStringSubmatch(str)
 	if len(match) == 0 {
 		return nil, fmt.Errorf("type format is incorrect. Expected 'type''bytes' but found '%s'", str)
@@ -512,6 +801,16 @@ func decodeSimpleType(str string) (*Type, error) {
 		return nil, fmt.Errorf("type %s does not expect bytes", t)
 	}
 
+	if t == "int" || t == "uint" {
+		if bytes == 0 || bytes > 256 || bytes%8 != 0 {
+			return nil, fmt.Errorf("invalid size %d for type %s, it has to be a multiple of 8 up to 256", bytes, t)
+		}
+	} else if t == "bytes" && ok {
+		if bytes == 0 || bytes > 32 {
+			return nil, fmt.Errorf("invalid size %d for type bytes, it has to be between 1 and 32", bytes)
+		}
+	}
+
 	switch t {
 	case "uint":
 		var k reflect.Type
@@ -525,9 +824,6 @@ func decodeSimpleType(str string) (*Type, error) {
 		case 64:
 			k = uint64T
 		default:
-			if bytes%8 != 0 {
-				panic(fmt.Errorf("number of bytes has to be M mod 8"))
-			}
 			k = bigIntT
 		}
 		return &Type{kind: KindUInt, size: int(bytes), t: k}, nil
@@ -544,9 +840,6 @@ func decodeSimpleType(str string) (*Type, error) {
 		case 64:
 			k = int64T
 		default:
-			if bytes%8 != 0 {
-				panic(fmt.Errorf("number of bytes has to be M mod 8"))
-			}
 			k = bigIntT
 		}
 		return &Type{kind: KindInt, size: int(bytes), t: k}, nil
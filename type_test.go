@@ -1,11 +1,13 @@
 package abi
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/umbracle/ethgo"
 )
 
 func TestType(t *testing.T) {
@@ -324,6 +326,22 @@ func TestType(t *testing.T) {
 			s:   "tuple(a int32,",
 			err: true,
 		},
+		{
+			s:   "uint7",
+			err: true,
+		},
+		{
+			s:   "int264",
+			err: true,
+		},
+		{
+			s:   "bytes33",
+			err: true,
+		},
+		{
+			s:   "bytes0",
+			err: true,
+		},
 	}
 
 	for _, c := range cases {
@@ -364,6 +382,35 @@ func TestType(t *testing.T) {
 	}
 }
 
+func TestType_HumanReadable(t *testing.T) {
+	cases := []struct {
+		s string
+		r string
+	}{
+		{"tuple(address to, uint256 amount)[]", "tuple(address to,uint256 amount)[]"},
+		{"(address to, uint256 amount)", "tuple(address to,uint256 amount)"},
+		{"uint256[3][]", "uint256[3][]"},
+		{"tuple( address to , tuple(uint8 a, bytes b) inner )[2][]", "tuple(address to,tuple(uint8 a,bytes b) inner)[2][]"},
+	}
+
+	for _, c := range cases {
+		typ, err := NewType(c.s)
+		require.NoError(t, err)
+		assert.Equal(t, c.r, typ.Format(true))
+	}
+
+	typ := MustNewType("tuple(address to, uint256 amount)[]")
+	assert.Equal(t, KindSlice, typ.Kind())
+	assert.Equal(t, "amount", typ.Elem().TupleElems()[1].Name)
+	assert.Equal(t, KindUInt, typ.Elem().TupleElems()[1].Elem.Kind())
+
+	_, err := NewType("uint7")
+	require.EqualError(t, err, "invalid size 7 for type uint, it has to be a multiple of 8 up to 256")
+
+	_, err = NewType("tuple(address to, bytes33 data)")
+	require.EqualError(t, err, "failed to decode type: invalid size 33 for type bytes, it has to be between 1 and 32")
+}
+
 func TestTypeArgument_InternalFields(t *testing.T) {
 	arg := &ArgumentStr{
 		Type: "tuple",
@@ -424,7 +471,7 @@ func TestSize(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			size := getTypeSize(tt)
+			size := getTypeSize(tt, 32)
 			if size != c.Size {
 				t.Fatalf("expected size %d but found %d", c.Size, size)
 			}
@@ -437,3 +484,173 @@ func simpleType(s string) *ArgumentStr {
 		Type: s,
 	}
 }
+
+func TestTypeLeaves(t *testing.T) {
+	cases := []struct {
+		typ    string
+		leaves []string
+	}{
+		{"uint256", []string{"uint256"}},
+		{"tuple(uint256,tuple(address,bytes32))", []string{"uint256", "address", "bytes32"}},
+		{"tuple(uint8[2] a, string b)", []string{"uint8", "uint8", "string"}},
+		{"tuple(uint8,tuple(address,bool)[])", []string{"uint8", "tuple(address,bool)[]"}},
+		{"tuple()", []string{}},
+	}
+
+	for _, c := range cases {
+		leaves := []string{}
+		for _, leaf := range MustNewType(c.typ).Leaves() {
+			leaves = append(leaves, leaf.String())
+		}
+		assert.Equal(t, c.leaves, leaves)
+	}
+}
+
+func TestTypeByteSize(t *testing.T) {
+	cases := []struct {
+		typ  string
+		size int
+		ok   bool
+	}{
+		{"uint256", 32, true},
+		{"int24", 3, true},
+		{"bool", 1, true},
+		{"address", 20, true},
+		{"bytes5", 5, true},
+		{"function", 24, true},
+		{"uint16[3]", 96, true},
+		{"tuple(address,uint8[2],tuple(bool,bytes2))", 87, true},
+		{"bytes", 0, false},
+		{"string[2]", 0, false},
+		{"tuple(uint8,uint8[])", 0, false},
+	}
+
+	for _, c := range cases {
+		size, ok := MustNewType(c.typ).ByteSize()
+		assert.Equal(t, c.size, size, c.typ)
+		assert.Equal(t, c.ok, ok, c.typ)
+	}
+
+	// tight arrays do not pad the elements
+	size, ok := packedByteSize(MustNewType("uint16[3]"), true)
+	assert.True(t, ok)
+	assert.Equal(t, 6, size)
+
+	size, ok = packedByteSize(MustNewType("tuple(address,uint8[2],tuple(bool,bytes2))"), true)
+	assert.True(t, ok)
+	assert.Equal(t, 25, size)
+}
+
+func TestTypeStripped(t *testing.T) {
+	typ, err := NewTypeFromArgument(&ArgumentStr{
+		Type:         "tuple",
+		InternalType: "struct Order",
+		Components: []*ArgumentStr{
+			{Name: "maker", Type: "address"},
+			{
+				Name: "items",
+				Type: "tuple[]",
+				Components: []*ArgumentStr{
+					{Name: "id", Type: "uint256"},
+					{Name: "data", Type: "bytes"},
+				},
+			},
+		},
+	})
+	require.NoError(t, err)
+	typ.SetLabel("Order")
+
+	stripped := typ.Stripped()
+	assert.Equal(t, "tuple(address,tuple(uint256,bytes)[])", stripped.String())
+	assert.Equal(t, stripped.String(), stripped.Format(true))
+	assert.Equal(t, "", stripped.InternalType())
+	assert.Equal(t, "", stripped.Label())
+
+	// the original type is not modified
+	assert.Equal(t, "tuple(address maker,tuple(uint256 id,bytes data)[] items)", typ.Format(true))
+}
+
+func TestTypeStandardSize(t *testing.T) {
+	cases := []struct {
+		typ  string
+		size int
+		ok   bool
+	}{
+		{"uint256", 32, true},
+		{"uint8", 32, true},
+		{"bytes4", 32, true},
+		{"tuple(uint256,address)", 64, true},
+		{"uint8[3]", 96, true},
+		{"tuple(bool,uint8[2])[2]", 192, true},
+		{"string", 32, false},
+		{"uint256[]", 32, false},
+		{"tuple(uint256,bytes)", 32, false},
+		{"string[2]", 32, false},
+	}
+
+	for _, c := range cases {
+		size, ok := MustNewType(c.typ).StandardSize()
+		assert.Equal(t, c.size, size, c.typ)
+		assert.Equal(t, c.ok, ok, c.typ)
+	}
+}
+
+func TestTypeBuilder(t *testing.T) {
+	items, err := NewSliceType(MustNewType("uint256"))
+	require.NoError(t, err)
+
+	pair, err := NewArrayType(MustNewType("address"), 2)
+	require.NoError(t, err)
+
+	typ, err := NewTupleTypeFromElems(
+		&TupleElem{Name: "items", Elem: items},
+		&TupleElem{Name: "pair", Elem: pair},
+	)
+	require.NoError(t, err)
+
+	// same type as the parsed one
+	parsed := MustNewType("tuple(uint256[] items, address[2] pair)")
+	assert.Equal(t, parsed.Format(true), typ.Format(true))
+	assert.Equal(t, parsed.GoType(), typ.GoType())
+
+	value := map[string]interface{}{
+		"items": []*big.Int{big.NewInt(1)},
+		"pair":  [2]ethgo.Address{{0x1}, {0x2}},
+	}
+	buf, err := Encode(value, typ)
+	require.NoError(t, err)
+
+	expected, err := Encode(value, parsed)
+	require.NoError(t, err)
+	assert.Equal(t, expected, buf)
+
+	// validation
+	_, err = NewArrayType(MustNewType("address"), 0)
+	require.ErrorContains(t, err, "array size has to be positive")
+
+	_, err = NewSliceType(nil)
+	require.ErrorContains(t, err, "no element type")
+
+	_, err = NewTupleTypeFromElems(&TupleElem{Name: "a", Elem: items}, &TupleElem{Name: "a", Elem: pair})
+	require.ErrorContains(t, err, "repeated element 'a'")
+
+	_, err = NewTupleTypeFromElems(&TupleElem{Name: "a"})
+	require.ErrorContains(t, err, "tuple element 0 has no type")
+}
+
+func TestTypeSignature(t *testing.T) {
+	cases := []struct {
+		typ string
+		sig string
+	}{
+		{"uint256", "uint256"},
+		{"tuple(address a, uint256 b)", "(address,uint256)"},
+		{"tuple(address maker, tuple(uint256 id, bytes data)[] items)", "(address,(uint256,bytes)[])"},
+		{"tuple(tuple(bool)[2][] a)", "((bool)[2][])"},
+		{"fixed", "fixed128x18"},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.sig, MustNewType(c.typ).Signature(), c.typ)
+	}
+}
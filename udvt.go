@@ -0,0 +1,88 @@
+package abi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// userTypeRegexp matches the identifiers accepted by the type parser
+var userTypeRegexp = regexp.MustCompile("^[a-zA-Z_][a-zA-Z0-9_]*$")
+
+// RegisterUserType registers a solidity user defined value type (i.e.
+// 'type OrderId is bytes32') with the name and its underlying elementary
+// type. The name can be used in the type strings and it is encoded like
+// the underlying type, which is also used in the canonical signatures.
+// Registering an existing name replaces its underlying type
+func RegisterUserType(name, underlying string) error {
+	if !userTypeRegexp.MatchString(name) || name == "tuple" {
+		return fmt.Errorf("'%s' is not a valid type name", name)
+	}
+	if _, err := decodeBuiltinType(name); err == nil {
+		return fmt.Errorf("'%s' is a builtin type", name)
+	}
+	typ, err := NewType(underlying)
+	if err != nil {
+		return fmt.Errorf("failed to parse the underlying type of '%s': %v", name, err)
+	}
+	switch typ.Kind() {
+	case KindBool, KindInt, KindUInt, KindAddress, KindFixedBytes, KindFixedPoint, KindFunction:
+	default:
+		return fmt.Errorf("the underlying type of '%s' has to be an elementary value type but found %s", name, typ.String())
+	}
+
+	updateRegistry(func(r *registry) {
+		r.userTypes[name] = typ.String()
+	})
+	return nil
+}
+
+// UnregisterUserType removes a registered user defined value type
+func UnregisterUserType(name string) {
+	updateRegistry(func(r *registry) {
+		delete(r.userTypes, name)
+	})
+}
+
+func getUserType(name string) (string, bool) {
+	underlying, ok := loadRegistry().userTypes[name]
+	return underlying, ok
+}
+
+// decodeUserType parses a registered user defined value type. It returns
+// false if the name is not registered
+func decodeUserType(name string) (*Type, bool, error) {
+	underlying, ok := getUserType(name)
+	if !ok {
+		return nil, false, nil
+	}
+	typ, err := decodeBuiltinType(underlying)
+	if err != nil {
+		return nil, true, err
+	}
+	typ.udvt = name
+	typ.label = name
+	return typ, true, nil
+}
+
+// fillInUserType sets the name of the user defined value type of an
+// abi argument with the internal type of a registered type whose
+// underlying type matches (i.e. 'OrderId' or 'Exchange.OrderId')
+func fillInUserType(typ *Type, itype string) {
+	name := itype
+	if indx := strings.LastIndex(name, "."); indx != -1 {
+		name = name[indx+1:]
+	}
+	if underlying, ok := getUserType(name); ok && underlying == typ.String() {
+		typ.udvt = name
+		if typ.label == "" {
+			typ.label = name
+		}
+	}
+}
+
+// UserType returns the name of the user defined value type or an empty
+// string if the type is not a user defined value type
+func (t *Type) UserType() string {
+	return t.udvt
+}
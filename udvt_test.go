@@ -0,0 +1,68 @@
+package abi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserType(t *testing.T) {
+	require.NoError(t, RegisterUserType("OrderId", "bytes32"))
+	defer UnregisterUserType("OrderId")
+
+	typ, err := NewType("OrderId")
+	require.NoError(t, err)
+	require.Equal(t, KindFixedBytes, typ.Kind())
+	require.Equal(t, "OrderId", typ.UserType())
+	require.Equal(t, "bytes32", typ.String())
+	require.Equal(t, "OrderId", typ.Format(true))
+
+	// encoded like the underlying type
+	id := [32]byte{0x1, 0x2}
+	res, err := EncodePacked(id, typ)
+	require.NoError(t, err)
+	require.Equal(t, id[:], res)
+
+	val, err := DecodePacked(typ, res)
+	require.NoError(t, err)
+	require.Equal(t, id, val)
+
+	// nested in tuples and arrays
+	tuple := MustNewType("tuple(OrderId[] ids, address owner)")
+	require.Equal(t, "tuple(bytes32[],address)", tuple.String())
+
+	// the errors have the name of the type
+	_, err = DecodePacked(typ, []byte{0x1})
+	require.ErrorContains(t, err, "OrderId")
+}
+
+func TestUserType_Register(t *testing.T) {
+	require.ErrorContains(t, RegisterUserType("uint256", "uint8"), "builtin type")
+	require.ErrorContains(t, RegisterUserType("Name", "string"), "elementary value type")
+	require.ErrorContains(t, RegisterUserType("Ids", "uint8[]"), "elementary value type")
+	require.ErrorContains(t, RegisterUserType("Bad", "uint7"), "failed to parse")
+	require.ErrorContains(t, RegisterUserType("Foo[]", "uint256"), "'Foo[]' is not a valid type name")
+	require.ErrorContains(t, RegisterUserType("tuple", "uint256"), "not a valid type name")
+
+	// registered names can be registered again
+	require.NoError(t, RegisterUserType("Amount", "uint128"))
+	defer UnregisterUserType("Amount")
+	require.NoError(t, RegisterUserType("Amount", "uint256"))
+	require.Equal(t, "uint256", MustNewType("Amount").String())
+}
+
+func TestUserType_InternalType(t *testing.T) {
+	require.NoError(t, RegisterUserType("Price", "uint128"))
+	defer UnregisterUserType("Price")
+
+	arg := &ArgumentStr{Type: "uint128", InternalType: "Market.Price"}
+	typ, err := NewTypeFromArgument(arg)
+	require.NoError(t, err)
+	require.Equal(t, "Price", typ.UserType())
+
+	// the internal type is ignored if the underlying type does not match
+	arg = &ArgumentStr{Type: "uint64", InternalType: "Price"}
+	typ, err = NewTypeFromArgument(arg)
+	require.NoError(t, err)
+	require.Equal(t, "", typ.UserType())
+}
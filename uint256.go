@@ -0,0 +1,69 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/holiman/uint256"
+)
+
+var (
+	uint256T    = reflect.TypeOf(new(uint256.Int))
+	uint256ValT = reflect.TypeOf(uint256.Int{})
+)
+
+// uint256ToBig returns the value of an *uint256.Int or uint256.Int as a
+// big integer. It returns false if the value is not an uint256
+func uint256ToBig(v reflect.Value) (*big.Int, bool) {
+	switch v.Type() {
+	case uint256T:
+		if v.IsNil() {
+			return nil, false
+		}
+		return v.Interface().(*uint256.Int).ToBig(), true
+
+	case uint256ValT:
+		n := v.Interface().(uint256.Int)
+		return n.ToBig(), true
+
+	default:
+		return nil, false
+	}
+}
+
+// putUint256 returns the lowest size bits of the number in big endian
+func putUint256(n *uint256.Int, size int) []byte {
+	buf := n.Bytes32()
+	return append([]byte{}, buf[32-size/8:]...)
+}
+
+// readUint256 reads an unsigned integer of up to 256 bits
+func readUint256(b []byte) *uint256.Int {
+	return new(uint256.Int).SetBytes(b)
+}
+
+// uint256Hook converts big integers into uint256 fields and uint256
+// values into big integers for the other fields
+func uint256Hook(to reflect.Type, data interface{}) (interface{}, error) {
+	switch n := data.(type) {
+	case *big.Int:
+		if (to != uint256T && to != uint256ValT) || n == nil {
+			return data, nil
+		}
+		res, overflow := uint256.FromBig(n)
+		if overflow || n.Sign() < 0 {
+			return nil, fmt.Errorf("value %s overflows %s", n.String(), to.String())
+		}
+		return res, nil
+
+	case *uint256.Int:
+		if to == uint256T || to == uint256ValT || n == nil {
+			return data, nil
+		}
+		return n.ToBig(), nil
+
+	default:
+		return data, nil
+	}
+}
@@ -0,0 +1,80 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUint256_Encode(t *testing.T) {
+	n := uint256.MustFromHex("0x102030405060708090a")
+	expected := new(big.Int).SetBytes([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+	for _, typ := range []string{"uint256", "uint128", "uint80", "int256"} {
+		res, err := EncodePacked(n, MustNewType(typ))
+		require.NoError(t, err)
+
+		big, err := EncodePacked(expected, MustNewType(typ))
+		require.NoError(t, err)
+		require.Equal(t, big, res, typ)
+
+		// values and the reflection path
+		res, err = EncodePackedWithOptions(*n, MustNewType(typ), &EncodeOptions{CheckRange: true})
+		require.NoError(t, err)
+		require.Equal(t, big, res, typ)
+	}
+
+	res, err := Encode(n, MustNewType("uint256"))
+	require.NoError(t, err)
+	require.Equal(t, n.Bytes32(), [32]byte(res))
+
+	res, err = AppendPacked([]byte{0xff}, n, MustNewType("uint128"))
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0xff}, make([]byte, 6)...), res[:7])
+	require.Equal(t, expected.Bytes(), res[7:])
+
+	_, err = EncodePackedWithOptions(n, MustNewType("uint64"), &EncodeOptions{CheckRange: true})
+	require.Error(t, err)
+}
+
+func TestUint256_Decode(t *testing.T) {
+	opts := &DecodeOptions{Uint256: true}
+	n := uint256.NewInt(300)
+
+	input, err := EncodePacked(n, MustNewType("uint256"))
+	require.NoError(t, err)
+
+	res, err := DecodePackedWithOptions(MustNewType("uint256"), input, opts)
+	require.NoError(t, err)
+	require.Equal(t, n, res)
+
+	// signed and small integers are not affected
+	res, err = DecodePackedWithOptions(MustNewType("int256"), input, opts)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(300), res)
+
+	input, err = EncodePacked([]interface{}{n, n}, MustNewType("uint128[]"))
+	require.NoError(t, err)
+
+	res, err = DecodePackedWithOptions(MustNewType("uint128[]"), input, opts)
+	require.NoError(t, err)
+	require.Equal(t, []*uint256.Int{n, n}, res)
+
+	// struct fields
+	type Value struct {
+		A *uint256.Int
+		B *big.Int
+	}
+	typ := MustNewType("tuple(uint256 a, uint256 b)")
+	input, err = EncodePacked(map[string]interface{}{"a": n, "b": n}, typ)
+	require.NoError(t, err)
+
+	out, err := DecodePackedAs[Value](typ, input)
+	require.NoError(t, err)
+	require.Equal(t, Value{A: n, B: big.NewInt(300)}, out)
+
+	_, err = DecodePackedAs[uint256.Int](MustNewType("int8"), []byte{0xff})
+	require.Error(t, err)
+}
@@ -0,0 +1,71 @@
+package abi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// SkipType can be returned by a WalkFunc to skip the elements of the
+// visited type (i.e. the tuple elements or the array elements)
+var SkipType = errors.New("skip type")
+
+// WalkFunc is called by Walk for each type of the tree with its path
+// from the root. The path has the names (or the positions if unnamed) of
+// the tuple elements and '[]' for the elements of arrays and slices
+// (i.e. 'order.items[].id'). The root has an empty path
+type WalkFunc func(path string, t *Type) error
+
+// Walk visits the type and then its elements in depth-first order. The
+// walk stops with the first error returned by fn other than SkipType
+func Walk(t *Type, fn WalkFunc) error {
+	return walk("", t, fn)
+}
+
+func walk(path string, t *Type, fn WalkFunc) error {
+	if err := fn(path, t); err != nil {
+		if err == SkipType {
+			return nil
+		}
+		return err
+	}
+
+	switch t.Kind() {
+	case KindTuple:
+		for indx, elem := range t.TupleElems() {
+			name := tupleElemName(elem, indx)
+			if path != "" {
+				name = path + "." + name
+			}
+			if err := walk(name, elem.Elem, fn); err != nil {
+				return err
+			}
+		}
+
+	case KindArray, KindSlice:
+		if err := walk(path+"[]", t.Elem(), fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FieldPaths returns the paths of the elementary types of the tree
+// (everything but tuples, arrays and slices) with their types
+func FieldPaths(t *Type) (map[string]*Type, error) {
+	res := map[string]*Type{}
+	err := Walk(t, func(path string, typ *Type) error {
+		switch typ.Kind() {
+		case KindTuple, KindArray, KindSlice:
+			return nil
+		}
+		if _, ok := res[path]; ok {
+			return fmt.Errorf("repeated path '%s'", path)
+		}
+		res[path] = typ
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
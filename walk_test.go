@@ -0,0 +1,61 @@
+package abi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalk(t *testing.T) {
+	typ := MustNewType("tuple(address maker, tuple(uint256 id, bytes data)[] items, uint8[2])")
+
+	visited := []string{}
+	err := Walk(typ, func(path string, t *Type) error {
+		visited = append(visited, fmt.Sprintf("%s:%s", path, t.Kind()))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		":Tuple",
+		"maker:Address",
+		"items:Slice",
+		"items[]:Tuple",
+		"items[].id:Uint",
+		"items[].data:Bytes",
+		"2:Array",
+		"2[]:Uint",
+	}, visited)
+
+	// skip the elements of the slice
+	visited = []string{}
+	err = Walk(typ, func(path string, t *Type) error {
+		visited = append(visited, path)
+		if t.Kind() == KindSlice {
+			return SkipType
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"", "maker", "items", "2", "2[]"}, visited)
+
+	// stop with the first error
+	err = Walk(typ, func(path string, t *Type) error {
+		if t.Kind() == KindBytes {
+			return fmt.Errorf("bytes not allowed at '%s'", path)
+		}
+		return nil
+	})
+	require.EqualError(t, err, "bytes not allowed at 'items[].data'")
+}
+
+func TestFieldPaths(t *testing.T) {
+	paths, err := FieldPaths(MustNewType("tuple(address maker, tuple(uint256 id)[] items)"))
+	require.NoError(t, err)
+	require.Len(t, paths, 2)
+	require.Equal(t, "address", paths["maker"].String())
+	require.Equal(t, "uint256", paths["items[].id"].String())
+
+	_, err = FieldPaths(MustNewType("tuple(address a, uint256 a)"))
+	require.ErrorContains(t, err, "repeated path 'a'")
+}